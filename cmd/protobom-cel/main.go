@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+// protobom-cel evaluates CEL expressions against SBOM files using the
+// protobom CEL library.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/release-utils/version"
+
+	"github.com/protobom/cel/pkg/library"
+	"github.com/protobom/cel/pkg/runner"
+)
+
+type options struct {
+	expression string
+	file       string
+	enableIO   bool
+	format     string
+	explain    bool
+}
+
+// Validate checks the options before running the evaluation
+func (o *options) Validate() error {
+	if o.expression != "" && o.file != "" {
+		return errors.New("only one of --expression or --file can be specified")
+	}
+	if _, err := runner.FormatFromString(o.format); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readCode returns the CEL code to evaluate from the inline expression, the
+// code file or, if none are set, from STDIN.
+func (o *options) readCode(r *runner.Runner, stdin io.Reader) (string, error) {
+	if o.expression != "" {
+		return o.expression, nil
+	}
+
+	if o.file != "" && o.file != "-" {
+		f, err := os.Open(o.file)
+		if err != nil {
+			return "", fmt.Errorf("opening code file: %w", err)
+		}
+		defer f.Close() //nolint:errcheck
+
+		return r.ReadStream(f)
+	}
+
+	return r.ReadStream(stdin)
+}
+
+func rootCommand() *cobra.Command {
+	opts := &options{}
+	cmd := &cobra.Command{
+		Use:   "protobom-cel [flags] sbom.json [sbom.json...]",
+		Short: "Evaluate CEL expressions against SBOMs",
+		Long: `protobom-cel evaluates Common Expression Language (CEL) expressions
+against SBOM files using the protobom CEL library.
+
+The SBOMs passed as arguments are loaded into the sboms variable in the
+order they were specified. The expression can be passed inline, read from a
+file or read from STDIN:
+
+  protobom-cel -e 'sboms[0].get_packages().to_document()' sbom.spdx.json
+  protobom-cel -f examples/files.cel sbom.spdx.json
+  echo 'sboms[0].get_files()' | protobom-cel sbom.spdx.json
+
+If the expression evaluates to a Document, it is written as a native SBOM
+in the format set with --format (spdx, cyclonedx, cyclonedx-1.4, 1.5 or 1.6).
+Any other values are printed as JSON.
+
+Use --version to print the version information.
+
+With --explain, the value of each subexpression is printed instead of the
+result to help debugging expressions.
+`,
+		Args:          cobra.ArbitraryArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return opts.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runnerOpts := runner.DefaultOptions()
+			runnerOpts.LibraryOptions = []library.OptFunc{
+				library.WithEnableIO(opts.enableIO),
+			}
+
+			r, err := runner.NewRunnerWithOptions(&runnerOpts)
+			if err != nil {
+				return fmt.Errorf("creating runner: %w", err)
+			}
+
+			code, err := opts.readCode(r, cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("reading code: %w", err)
+			}
+
+			vars, err := runner.BuildVariables(
+				runner.WithPaths(args),
+				runner.WithLibraryOptions(runnerOpts.LibraryOptions...),
+			)
+			if err != nil {
+				return fmt.Errorf("loading SBOMs: %w", err)
+			}
+
+			if opts.explain {
+				e, err := r.Explain(cmd.Context(), code, vars)
+				if err != nil {
+					return err
+				}
+				return e.WriteListing(cmd.OutOrStdout())
+			}
+
+			result, err := r.Evaluate(code, vars)
+			if err != nil {
+				return err
+			}
+
+			format, err := runner.FormatFromString(opts.format)
+			if err != nil {
+				return err
+			}
+
+			return writeResult(cmd.OutOrStdout(), result, format)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.expression, "expression", "e", "", "CEL expression to evaluate")
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "file to read the CEL code from (- reads from STDIN)")
+	cmd.Flags().StringVar(&opts.format, "format", "spdx", "format to write document results in")
+	cmd.Flags().BoolVar(&opts.enableIO, "enable-io", false, "enable the functions that access the filesystem or network")
+	cmd.Flags().BoolVar(&opts.explain, "explain", false, "print the value of each subexpression instead of the result")
+
+	// The version is a flag and not a subcommand as the positional arguments
+	// are SBOM paths, a file named "version" would run the subcommand.
+	info := version.GetVersionInfo()
+	info.Name = "protobom-cel"
+	info.FontName = "doom"
+	cmd.Version = info.String()
+	cmd.SetVersionTemplate("{{.Version}}")
+	return cmd
+}
+
+func main() {
+	if err := rootCommand().Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
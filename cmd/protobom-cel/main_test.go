@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootCommand(t *testing.T) {
+	codeFile := filepath.Join(t.TempDir(), "code.cel")
+	require.NoError(t, os.WriteFile(codeFile, []byte("sboms.size()"), 0o600))
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		stdin    string
+		expected string
+		contains string
+		mustErr  bool
+	}{
+		{"expression", []string{"-e", "sboms.size()", testSBOM}, "", "1\n", "", false},
+		{"file", []string{"-f", codeFile, testSBOM, testSBOM}, "", "2\n", "", false},
+		{"stdin", []string{testSBOM}, "sboms[0].get_packages().get_nodes().size()", "14\n", "", false},
+		{"stdin-dash", []string{"-f", "-"}, "sboms.size()", "0\n", "", false},
+		{"document", []string{"-e", "sboms[0].get_packages().to_document()", "--format", "cdx-1.5", testSBOM}, "", "", `"specVersion": "1.5"`, false},
+		{"sbom-named-version", []string{"-e", "sboms.size()", "version"}, "", "", "", true},
+		{"version", []string{"--version"}, "", "", "GitVersion:", false},
+		{"expression-and-file", []string{"-e", "1", "-f", codeFile}, "", "", "", true},
+		{"invalid-format", []string{"-e", "1", "--format", "spdx-2.2"}, "", "", "", true},
+		{"compile-error", []string{"-e", "sboms[0].nope()", testSBOM}, "", "", "", true},
+		{"missing-sbom", []string{"-e", "1", "nope.spdx.json"}, "", "", "", true},
+		{"io-disabled", []string{"-e", `protobom.load_sbom("` + testSBOM + `").get_packages().get_nodes().size()`}, "", "", "", true},
+		{"io-enabled", []string{"--enable-io", "-e", `protobom.load_sbom("` + testSBOM + `").get_packages().get_nodes().size()`}, "", "14\n", "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := rootCommand()
+			cmd.SetArgs(tc.args)
+			cmd.SetIn(strings.NewReader(tc.stdin))
+			cmd.SetOut(&out)
+
+			err := cmd.Execute()
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.contains != "" {
+				require.Contains(t, out.String(), tc.contains)
+				return
+			}
+			require.Equal(t, tc.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/protobom/cel/pkg/runner"
+)
+
+// writeResult outputs the evaluation result. Documents are written as native
+// SBOMs in the specified format, anything else is rendered as JSON. Some
+// functions return no value (eg get_node_by_id when the node is not found),
+// those results are written as null.
+func writeResult(w io.Writer, result ref.Val, format formats.Format) error {
+	if result == nil {
+		result = types.NullValue
+	}
+
+	if _, ok := result.Value().(*sbom.Document); ok {
+		return runner.WriteDocument(w, result, format)
+	}
+
+	data, err := toJSONValue(result)
+	if err != nil {
+		return fmt.Errorf("rendering result: %w", err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		return fmt.Errorf("encoding result: %w", err)
+	}
+	return nil
+}
+
+// toJSONValue converts a CEL value into a structure that can be encoded as
+// JSON. Protobom elements are rendered using their protobuf JSON form.
+func toJSONValue(val ref.Val) (any, error) {
+	if msg, ok := val.Value().(proto.Message); ok {
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling %T: %w", msg, err)
+		}
+		return json.RawMessage(data), nil
+	}
+
+	switch v := val.(type) {
+	case traits.Lister:
+		ret := []any{}
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			item, err := toJSONValue(it.Next())
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, item)
+		}
+		return ret, nil
+	case traits.Mapper:
+		ret := map[string]any{}
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			key := it.Next()
+			item, err := toJSONValue(v.Get(key))
+			if err != nil {
+				return nil, err
+			}
+			ret[fmt.Sprintf("%v", key.Value())] = item
+		}
+		return ret, nil
+	}
+
+	native, err := val.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return val.Value(), nil //nolint:nilerr // Fall back to the raw value
+	}
+	data, err := protojson.Marshal(native.(*structpb.Value)) //nolint:forcetypeassert // Conversion returns the requested type
+	if err != nil {
+		return nil, fmt.Errorf("marshaling value: %w", err)
+	}
+	return json.RawMessage(data), nil
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/runner"
+)
+
+const testSBOM = "../../examples/curl.spdx.json"
+
+func TestWriteResult(t *testing.T) {
+	r, err := runner.NewRunner()
+	require.NoError(t, err)
+
+	vars, err := runner.BuildVariables(runner.WithPaths([]string{testSBOM}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		code     string
+		expected string
+	}{
+		{"int", "1 + 2", "3\n"},
+		{"string", `"protobom"`, "\"protobom\"\n"},
+		{"bool", "true", "true\n"},
+		{"null", "null", "null\n"},
+		{"list", `[1, "a", false]`, "[\n  1,\n  \"a\",\n  false\n]\n"},
+		{"empty-list", "[]", "[]\n"},
+		{"map", `{"b": [2], "a": 1}`, "{\n  \"a\": 1,\n  \"b\": [\n    2\n  ]\n}\n"},
+		{"element", "sboms[0].get_packages().get_nodes()[0]", ""},
+		{"not-found", `sboms[0].get_node_by_id("nope")`, "null\n"},
+		{"elements", "sboms[0].get_packages().get_nodes().map(n, n.id)", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := r.Evaluate(tc.code, vars)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, writeResult(&buf, result, formats.SPDX23JSON))
+			if tc.expected != "" {
+				require.Equal(t, tc.expected, buf.String())
+				return
+			}
+			require.True(t, json.Valid(buf.Bytes()), buf.String())
+		})
+	}
+}
+
+func TestWriteResultDocument(t *testing.T) {
+	r, err := runner.NewRunner()
+	require.NoError(t, err)
+
+	vars, err := runner.BuildVariables(runner.WithPaths([]string{testSBOM}))
+	require.NoError(t, err)
+
+	result, err := r.Evaluate("sboms[0].get_packages().to_document()", vars)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		format  formats.Format
+		key     string
+		version string
+	}{
+		{formats.SPDX23JSON, "spdxVersion", "SPDX-2.3"},
+		{formats.CDX14JSON, "specVersion", "1.4"},
+		{formats.CDX15JSON, "specVersion", "1.5"},
+		{formats.CDX16JSON, "specVersion", "1.6"},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeResult(&buf, result, tc.format))
+
+			data := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+			require.Equal(t, tc.version, data[tc.key])
+		})
+	}
+}
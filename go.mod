@@ -3,8 +3,11 @@ module github.com/protobom/cel
 go 1.25.11
 
 require (
+	github.com/package-url/packageurl-go v0.1.3
 	github.com/protobom/protobom v0.5.8
+	github.com/spf13/cobra v1.10.2
 	github.com/stretchr/testify v1.11.1
+	go.yaml.in/yaml/v3 v3.0.4
 	sigs.k8s.io/release-utils v0.12.4
 )
 
@@ -24,8 +27,6 @@ require (
 	github.com/olekukonko/ll v0.1.6 // indirect
 	github.com/olekukonko/tablewriter v1.1.4 // indirect
 	github.com/sirupsen/logrus v1.9.4 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 )
 
@@ -7,6 +7,7 @@ import (
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
 	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/protobom/cel/pkg/elements"
 )
@@ -40,8 +41,43 @@ func (ProtobomTypeAdapter) NativeToValue(value any) ref.Val {
 		return &elements.Node{Node: v}
 	case *sbom.Person:
 		return &elements.Person{Person: v}
+	case *sbom.Property:
+		return &elements.Property{Property: v}
+	// Repeated and map fields read from statically typed elements
+	case protoreflect.List:
+		if _, ok := v.NewElement().Interface().(string); ok {
+			strs := make([]string, 0, v.Len())
+			for i := range v.Len() {
+				strs = append(strs, v.Get(i).String())
+			}
+			return types.NewStringList(ProtobomTypeAdapter{}, strs)
+		}
+		vals := make([]ref.Val, 0, v.Len())
+		for i := range v.Len() {
+			vals = append(vals, ProtobomTypeAdapter{}.NativeToValue(reflectValue(v.Get(i))))
+		}
+		return types.NewRefValList(ProtobomTypeAdapter{}, vals)
+	case protoreflect.Map:
+		vals := map[ref.Val]ref.Val{}
+		v.Range(func(k protoreflect.MapKey, val protoreflect.Value) bool {
+			vals[ProtobomTypeAdapter{}.NativeToValue(k.Interface())] = ProtobomTypeAdapter{}.NativeToValue(reflectValue(val))
+			return true
+		})
+		return types.NewRefValMap(ProtobomTypeAdapter{}, vals)
 	}
 
 	// let the default adapter handle other cases
 	return types.DefaultTypeAdapter.NativeToValue(value)
 }
+
+// reflectValue returns the native value of a protobuf field value
+func reflectValue(v protoreflect.Value) any {
+	switch i := v.Interface().(type) {
+	case protoreflect.Message:
+		return i.Interface()
+	case protoreflect.EnumNumber:
+		return int64(i)
+	default:
+		return i
+	}
+}
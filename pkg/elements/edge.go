@@ -60,7 +60,9 @@ func (e *Edge) Value() any {
 
 var _ traits.Indexer = (*Edge)(nil)
 
-// Get is the getter to implement the indexer trait
+// Get is the getter to implement the indexer trait. It supports the type
+// (returned as the protobom relationship name, eg "dependsOn"), from and to
+// keys.
 func (e *Edge) Get(index ref.Val) ref.Val {
 	switch v := index.Value().(type) {
 	case string:
@@ -4,6 +4,7 @@
 package elements_test
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -95,8 +96,9 @@ func TestGetMetadataGet(t *testing.T) {
 		}},
 		{"name", "sboms[0].get_metadata().get_authors()", false, func(t *testing.T, v ref.Val) {
 			t.Helper()
+			native, err := v.ConvertToNative(reflect.TypeOf([]*sbom.Person{}))
 			require.NoError(t, err)
-			docdata, ok := v.Value().([]*sbom.Person)
+			docdata, ok := native.([]*sbom.Person)
 			require.True(t, ok)
 			expect := []*sbom.Person{{
 				Name: "Dependabot (bot@dependa.net)",
@@ -4,6 +4,7 @@
 package elements_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/google/cel-go/common/types/ref"
@@ -42,6 +43,67 @@ func TestNodeGet(t *testing.T) {
 			t.Helper()
 			require.Equal(t, []string{"Apache-2.0"}, v.Value())
 		}},
+		{"purl", `sboms[0].node_list.get_node_by_id("npm-ansi-regex-5.0.1").purl()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			p, err := v.ConvertToNative(reflect.TypeOf(map[string]any{}))
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{
+				"type": "npm", "namespace": "", "name": "ansi-regex", "version": "5.0.1",
+				"qualifiers": map[string]string{}, "subpath": "",
+			}, p)
+		}},
+		{"purl-field", `sboms[0].node_list.get_root_nodes()[0].purl().namespace`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "kubernetes-sigs", v.Value())
+		}},
+		{"version-in-range", `sboms[0].node_list.get_node_by_id("npm-ansi-regex-5.0.1").version_in_range(">=5.0.0|<5.0.10")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"version-in-range-vers", `sboms[0].node_list.get_node_by_id("npm-ansi-regex-5.0.1").version_in_range("vers:npm/<5.0.1")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, false, v.Value())
+		}},
+		{"compare-versions", `protobom.compare_versions("1.10.0", "1.9.0", "semver")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(1), v.Value())
+		}},
+		{"compare-versions-bad-scheme", `protobom.compare_versions("1.10.0", "1.9.0", "nope")`, true, nil},
+		{"license-ids", "sboms[0].node_list.get_root_nodes()[0].license_ids()", false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"Apache-2.0"}, ids)
+		}},
+		{"license-satisfies", `sboms[0].node_list.get_nodes().filter(n, n.license_ids().size() > 0).all(n, n.license_satisfies(["MIT", "ISC", "BSD-3-Clause", "CC-BY-4.0", "Apache-2.0"]))`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"is-copyleft", "sboms[0].node_list.get_nodes().exists(n, n.is_copyleft())", false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, false, v.Value())
+		}},
+		{"license-info", `protobom.license_info("MIT").osi_approved && protobom.license_info("GPL-2.0-only").copyleft`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"is-valid-license", `protobom.is_valid_license("MIT OR NotALicense")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, false, v.Value())
+		}},
+		{"with-version", `sboms[0].node_list.get_node_by_id("npm-ansi-regex-5.0.1").with_version("5.0.2").purl().version`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "5.0.2", v.Value())
+		}},
+		{"with-supplier", `sboms[0].node_list.get_node_by_id("npm-ansi-regex-5.0.1").with_supplier("ACME", "sbom@acme.example").get_suppliers()[0].email`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "sbom@acme.example", v.Value())
+		}},
+		{"replace-node", `sboms[0].node_list.replace_node(sboms[0].node_list.get_node_by_id("npm-ansi-regex-5.0.1").with_license_concluded("ISC")).get_node_by_id("npm-ansi-regex-5.0.1").license_concluded == "ISC" && sboms[0].node_list.get_node_by_id("npm-ansi-regex-5.0.1").license_concluded == "MIT"`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"with-license-invalid", `sboms[0].node_list.get_root_nodes()[0].with_license_concluded("MIT AND")`, true, nil},
 		// TODO(puerco): More SBOMs, testa ll fiuelds
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -6,6 +6,7 @@ package elements
 import (
 	"fmt"
 	"reflect"
+	"slices"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/checker/decls"
@@ -18,6 +19,15 @@ import (
 var (
 	NodeListObject = decls.NewObjectType("protobom.protobom.NodeList")
 	NodeListType   = cel.ObjectType("protobom.protobom.NodeList")
+
+	// nodeListValueType is the type reported by NodeList values at runtime.
+	// It carries the adder trait so that the + operator dispatches to
+	// NodeList.Add. The trait cannot be set in NodeListType as the protobuf
+	// descriptor registers the type name with its own trait mask.
+	nodeListValueType = cel.ObjectType(
+		"protobom.protobom.NodeList",
+		traits.AdderType, traits.FieldTesterType, traits.IndexerType,
+	)
 )
 
 type NodeList struct {
@@ -60,7 +70,7 @@ func (nl *NodeList) Equal(other ref.Val) ref.Val {
 
 // Type implements ref.Val.Type.
 func (*NodeList) Type() ref.Type {
-	return NodeListType
+	return nodeListValueType
 }
 
 // Value implements ref.Val.Value.
@@ -68,23 +78,43 @@ func (nl *NodeList) Value() any {
 	return nl.NodeList
 }
 
-// Add should at least merge two nodelists together.
-func (nl *NodeList) Add(incoming ref.Val) {
+var _ traits.Adder = (*NodeList)(nil)
+
+// Add implements the adder trait. It returns a new NodeList combining the
+// nodes, edges and root elements of both nodelists. Nodes already present
+// in the receiver (by ID) are not duplicated. Neither operand is modified.
+func (nl *NodeList) Add(incoming ref.Val) ref.Val {
 	newNodeList, ok := incoming.(*NodeList)
 	if !ok {
-		// Here we should have a method to err
-		return
+		return types.MaybeNoSuchOverloadErr(incoming)
+	}
+
+	ret := &NodeList{NodeList: &sbom.NodeList{}}
+	if nl.NodeList != nil {
+		ret.NodeList = nl.NodeList.Copy()
+	}
+
+	if newNodeList.NodeList == nil {
+		return ret
 	}
 
 	for _, n := range newNodeList.Nodes {
-		if !nl.HasNodeWithID(n.Id) {
-			nl.Nodes = append(nl.Nodes, n)
+		if !ret.HasNodeWithID(n.Id) {
+			ret.Nodes = append(ret.Nodes, n.Copy())
 		}
 	}
 
 	for _, e := range newNodeList.Edges {
-		nl.AddEdge(e.From, e.Type, e.To)
+		ret.AddEdge(e.From, e.Type, slices.Clone(e.To))
 	}
+
+	for _, id := range newNodeList.RootElements {
+		if !slices.Contains(ret.RootElements, id) {
+			ret.RootElements = append(ret.RootElements, id)
+		}
+	}
+
+	return ret
 }
 
 // AddEsge adds edge data to
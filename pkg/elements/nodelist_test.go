@@ -0,0 +1,509 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package elements_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+	"github.com/protobom/cel/pkg/library"
+	"github.com/protobom/cel/pkg/runner"
+)
+
+func TestNodeListAdd(t *testing.T) {
+	r, err := runner.NewRunner()
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithPaths([]string{"testdata/github.spdx.json"}),
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		code    string
+		mustErr bool
+		eval    func(*testing.T, ref.Val)
+	}{
+		{"add", `sboms[0].get_nodes_by_purl_type("npm").add(sboms[0].get_nodes_by_purl_type("golang"))`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			nl, ok := v.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Len(t, nl.Nodes, 180)
+		}},
+		{"operator", `sboms[0].get_nodes_by_purl_type("npm") + sboms[0].get_nodes_by_purl_type("golang")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			nl, ok := v.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Len(t, nl.Nodes, 180)
+		}},
+		{"dedup", `size((sboms[0].node_list + sboms[0].node_list).get_nodes()) == size(sboms[0].node_list.get_nodes())`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"to-document", `(sboms[0].get_packages() + sboms[0].get_files()).to_document()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			doc, ok := v.Value().(*sbom.Document)
+			require.True(t, ok)
+			require.Len(t, doc.NodeList.Nodes, 192)
+		}},
+		{"operator-bad-type", `sboms[0].node_list + 1`, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := r.Evaluate(tc.code, vars)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.eval(t, ret)
+		})
+	}
+}
+
+func TestNodeListSetOperations(t *testing.T) {
+	r, err := runner.NewRunner()
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithPaths([]string{"testdata/github.spdx.json"}),
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		code    string
+		mustErr bool
+		nodes   int
+	}{
+		{"union", `sboms[0].get_nodes_by_purl_type("npm").union(sboms[0].get_nodes_by_purl_type("golang"))`, false, 180},
+		{"union-sbom", `sboms[0].union(sboms[0].get_nodes_by_purl_type("golang"))`, false, 192},
+		{"intersect", `sboms[0].intersect(sboms[0].get_nodes_by_purl_type("golang"))`, false, 67},
+		{"intersect-purl", `sboms[0].intersect(sboms[0].get_nodes_by_purl_type("golang"), "purl")`, false, 67},
+		{"difference", `sboms[0].difference(sboms[0].get_nodes_by_purl_type("npm"))`, false, 79},
+		{"symmetric-difference", `sboms[0].get_nodes_by_purl_type("npm").symmetric_difference(sboms[0].node_list)`, false, 79},
+		{"invalid-mode", `sboms[0].union(sboms[0], "name")`, true, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := r.Evaluate(tc.code, vars)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			nl, ok := ret.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Len(t, nl.Nodes, tc.nodes)
+		})
+	}
+}
+
+func TestNodeListQueries(t *testing.T) {
+	r, err := runner.NewRunner()
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithPaths([]string{"testdata/github.spdx.json"}),
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		code    string
+		mustErr bool
+		eval    func(*testing.T, ref.Val)
+	}{
+		{"exact", `sboms[0].get_nodes_by_purl("pkg:npm/ansi-regex@5.0.1").get_nodes().map(n, n.id)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"npm-ansi-regex-5.0.1"}, ids)
+		}},
+		{"versionless", `sboms[0].node_list.get_nodes_by_purl("pkg:npm/ansi-regex").get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(1), v.Value())
+		}},
+		{"glob", `sboms[0].get_nodes_by_purl("pkg:githubactions/actions/*").get_nodes().all(n, n.purl().namespace == "actions")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"glob-type", `sboms[0].get_nodes_by_purl("pkg:golang/*").get_nodes().size() == sboms[0].get_nodes_by_purl_type("golang").get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"invalid", `sboms[0].get_nodes_by_purl("npm/ansi-regex")`, true, nil},
+		{"by-license", `sboms[0].get_nodes_by_license("MIT").get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(95), v.Value())
+		}},
+		{"diff-self", `protobom.diff(sboms[0], sboms[0].node_list).changed.size() + protobom.diff(sboms[0], sboms[0]).added.get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(0), v.Value())
+		}},
+		{"diff-removed", `protobom.diff(sboms[0], sboms[0].difference(sboms[0].get_nodes_by_purl_type("npm"))).removed.to_document().node_list.get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(113), v.Value())
+		}},
+		{"diff-added-edges", `protobom.diff(sboms[0].get_nodes_by_purl_type("golang"), sboms[0]).added_edges.size() > 0`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"by-license-glob", `sboms[0].node_list.get_nodes_by_license("BSD-*").get_nodes().map(n, n.name).size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(2), v.Value())
+		}},
+		{"where", `sboms[0].where(n, n.name.startsWith("go:github.com/sigstore")).get_nodes().all(n, n.purl().type == "golang")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"where-nodelist", `sboms[0].node_list.where(n, n.purl().type == "npm").get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(113), v.Value())
+		}},
+		{"where-edges", `sboms[0].where(n, n.id in ["com.github.kubernetes-sigs-bom", "npm-ansi-regex-5.0.1"]).to_document()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			doc, ok := v.Value().(*sbom.Document)
+			require.True(t, ok)
+			require.Len(t, doc.NodeList.Nodes, 2)
+			require.Len(t, doc.NodeList.Edges, 1)
+			require.Equal(t, []string{"npm-ansi-regex-5.0.1"}, doc.NodeList.Edges[0].To)
+			require.Equal(t, []string{"com.github.kubernetes-sigs-bom"}, doc.NodeList.RootElements)
+		}},
+		{"where-roots", `sboms[0].where(n, n.id != "com.github.kubernetes-sigs-bom").root_elements.size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(191), v.Value())
+		}},
+		{"where-nested", `sboms[0].where(n, sboms[0].where(m, m.id == n.id).get_nodes().size() == 1).get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(192), v.Value())
+		}},
+		{"where-not-ident", `sboms[0].where(n.id, true)`, true, nil},
+		{"where-not-bool", `sboms[0].where(n, n.name)`, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := r.Evaluate(tc.code, vars)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.eval(t, ret)
+		})
+	}
+}
+
+func TestNodeListPaths(t *testing.T) {
+	r, err := runner.NewRunner()
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithPaths([]string{"testdata/github.spdx.json"}),
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		code    string
+		mustErr bool
+		eval    func(*testing.T, ref.Val)
+	}{
+		{"get-paths", `sboms[0].get_paths("com.github.kubernetes-sigs-bom", "npm-ansi-regex-5.0.1", 3)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			paths, ok := v.Value().([]ref.Val)
+			require.True(t, ok)
+			require.Len(t, paths, 1)
+		}},
+		{"get-paths-nodelist", `sboms[0].node_list.get_paths("com.github.kubernetes-sigs-bom", "npm-ansi-regex-5.0.1", 3, "CONTAINS")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			paths, ok := v.Value().([]ref.Val)
+			require.True(t, ok)
+			require.Empty(t, paths)
+		}},
+		{"get-shortest-path", `sboms[0].get_shortest_path("com.github.kubernetes-sigs-bom", "npm-ansi-regex-5.0.1").get_nodes().map(n, n.id)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"com.github.kubernetes-sigs-bom", "npm-ansi-regex-5.0.1"}, ids)
+		}},
+		{"get-shortest-path-document", `sboms[0].get_shortest_path("com.github.kubernetes-sigs-bom", "npm-ansi-regex-5.0.1", ["DEPENDS_ON"]).to_document()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			doc, ok := v.Value().(*sbom.Document)
+			require.True(t, ok)
+			require.Len(t, doc.NodeList.Edges, 1)
+		}},
+		{"get-node-ancestors", `sboms[0].get_node_ancestors("npm-ansi-regex-5.0.1", 1).get_nodes().map(n, n.id)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"npm-ansi-regex-5.0.1", "com.github.kubernetes-sigs-bom"}, ids)
+		}},
+		{"get-node-ancestors-nodelist", `sboms[0].node_list.get_node_ancestors("npm-ansi-regex-5.0.1", 5, "CONTAINS").get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(1), v.Value())
+		}},
+		{"get-node-ancestors-bad-type", `sboms[0].get_node_ancestors("npm-ansi-regex-5.0.1", 1, ["LIKES"])`, true, nil},
+		{"get-edges-by-type", `sboms[0].get_edges_by_type("DEPENDS_ON").size() == sboms[0].node_list.get_edges().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, true, v.Value())
+		}},
+		{"get-edges-by-type-list", `sboms[0].node_list.get_edges_by_type(["CONTAINS", "BUILD_TOOL_OF"])`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			edges, ok := v.Value().([]ref.Val)
+			require.True(t, ok)
+			require.Empty(t, edges)
+		}},
+		{"edge-type-index", `dyn(sboms[0].get_edges_by_type("dependsOn")[0]).type`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "dependsOn", v.Value())
+		}},
+		{"get-related", `sboms[0].get_related("npm-ansi-regex-5.0.1", "DEPENDS_ON").get_nodes().map(n, n.id)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"com.github.kubernetes-sigs-bom"}, ids)
+		}},
+		{"get-related-nodelist", `sboms[0].node_list.get_related("com.github.kubernetes-sigs-bom", ["CONTAINS"]).get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(0), v.Value())
+		}},
+		{"get-related-bad-type", `sboms[0].get_related("npm-ansi-regex-5.0.1", "LIKES")`, true, nil},
+		{"get-nodes-at-depth", `sboms[0].get_nodes_at_depth(0).get_nodes().map(n, n.id)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"com.github.kubernetes-sigs-bom"}, ids)
+		}},
+		{"get-nodes-at-depth-direct", `sboms[0].node_list.get_nodes_at_depth(1, "DEPENDS_ON").get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(191), v.Value())
+		}},
+		{"get-nodes-at-depth-edge-types", `sboms[0].get_nodes_at_depth(1, ["CONTAINS"]).get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(0), v.Value())
+		}},
+		{"get-graph-at-depth", `sboms[0].get_graph_at_depth(1).to_document()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			doc, ok := v.Value().(*sbom.Document)
+			require.True(t, ok)
+			require.Len(t, doc.NodeList.Nodes, 191)
+			require.Len(t, doc.NodeList.RootElements, 191)
+			require.Empty(t, doc.NodeList.Edges)
+		}},
+		{"depth-of", `sboms[0].depth_of("npm-ansi-regex-5.0.1")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(1), v.Value())
+		}},
+		{"depth-of-unreachable", `sboms[0].node_list.depth_of("npm-ansi-regex-5.0.1", "CONTAINS")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(-1), v.Value())
+		}},
+		{"depth-of-bad-type", `sboms[0].depth_of("npm-ansi-regex-5.0.1", "LIKES")`, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := r.Evaluate(tc.code, vars)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.eval(t, ret)
+		})
+	}
+}
+
+func TestNodeListTypes(t *testing.T) {
+	r, err := runner.NewRunner()
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithPaths([]string{"testdata/github.spdx.json"}),
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name       string
+		code       string
+		outputType *cel.Type
+		mustErr    bool
+	}{
+		{"root-nodes", "sboms[0].node_list.get_root_nodes()", cel.ListType(elements.NodeType), false},
+		{"nodes", "sboms[0].node_list.get_nodes()", cel.ListType(elements.NodeType), false},
+		{"nodes-by-name", `sboms[0].node_list.get_nodes_by_name("ansi-regex")`, cel.ListType(elements.NodeType), false},
+		{"suppliers", "sboms[0].node_list.get_root_nodes()[0].get_suppliers()", cel.ListType(elements.PersonType), false},
+		{"originators", "sboms[0].node_list.get_nodes()[0].get_originators()", cel.ListType(elements.PersonType), false},
+		{"authors", "sboms[0].get_metadata().get_authors()", cel.ListType(elements.PersonType), false},
+		{"author-name", "sboms[0].get_authors()[0].name", cel.StringType, false},
+		{"node-field", "sboms[0].node_list.get_root_nodes()[0].name", cel.StringType, false},
+		{"node-field-typo", "sboms[0].node_list.get_root_nodes()[0].nmae", nil, true},
+		{"node-method-typo", "sboms[0].node_list.get_nodes()[0].purrl()", nil, true},
+		{"person-field-typo", "sboms[0].get_authors()[0].nmae", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pq, err := r.Prepare(tc.code)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, pq.OutputType().IsExactType(tc.outputType), pq.OutputType().String())
+
+			// Runtime values match the declared type
+			_, err = pq.Evaluate(vars)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNodeListVulnerabilities(t *testing.T) {
+	opts := runner.DefaultOptions()
+	opts.LibraryOptions = []library.OptFunc{library.WithEnableIO(true)}
+	r, err := runner.NewRunnerWithOptions(&opts)
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithPaths([]string{"testdata/github.spdx.json", "testdata/github.spdx.json"}),
+	)
+	require.NoError(t, err)
+
+	const osvDB = `protobom.load_osv("../osv/testdata/osv")`
+	for _, tc := range []struct {
+		name    string
+		code    string
+		mustErr bool
+		eval    func(*testing.T, ref.Val)
+	}{
+		{"match", `protobom.match_vulnerabilities(sboms[0], ` + osvDB + `).map(m, m.node_id + ":" + m.vuln_id)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{
+				"npm-braces-3.0.2:GHSA-grv7-fg5c-xmjg",
+				"npm-postcss-7.0.39:GHSA-7fh5-64p2-3v2j",
+				"go-golang.org-x-crypto-0.11.0:GO-2023-2402",
+			}, ids)
+		}},
+		{"fields", `protobom.match_vulnerabilities(sboms[0].get_nodes_by_purl_type("golang"), ` + osvDB + `)[0]`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			m, err := v.ConvertToNative(reflect.TypeOf(map[string]any{}))
+			require.NoError(t, err)
+			require.Equal(t, map[string]any{
+				"node_id":  "go-golang.org-x-crypto-0.11.0",
+				"vuln_id":  "GO-2023-2402",
+				"aliases":  []string{"CVE-2023-48795", "GHSA-45x7-px36-x8w8"},
+				"summary":  "Man-in-the-middle attacker can compromise integrity of secure channel in golang.org/x/crypto",
+				"severity": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:N",
+				"fixed_in": []string{"0.17.0"},
+			}, m)
+		}},
+		{"with-vulnerabilities", `sboms[0].with_vulnerabilities(protobom.match_vulnerabilities(sboms[0], ` + osvDB + `)).get_node_by_id("npm-postcss-7.0.39").properties.filter(p, p.name == "vulnerability").map(p, p.data)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"GHSA-7fh5-64p2-3v2j"}, ids)
+		}},
+		{"where-vulnerable", `sboms[0].with_vulnerabilities(protobom.match_vulnerabilities(sboms[0], ` + osvDB + `)).where(n, n.properties.exists(p, p.name == "vulnerability")).get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(3), v.Value())
+		}},
+		{"bind", `cel.bind(db, ` + osvDB + `, protobom.match_vulnerabilities(sboms[0], db).size() + protobom.match_vulnerabilities(sboms[1], db).size())`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(6), v.Value())
+		}},
+		{"missing-data", `protobom.load_osv("../osv/testdata/nope")`, true, nil},
+		{"path", `protobom.match_vulnerabilities(sboms[0], "../osv/testdata/osv")`, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := r.Evaluate(tc.code, vars)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.eval(t, ret)
+		})
+	}
+
+	// Loading the database costs one unit per advisory
+	opts.CostLimit = 15
+	r, err = runner.NewRunnerWithOptions(&opts)
+	require.NoError(t, err)
+	_, err = r.Evaluate(`[protobom].size()`, vars)
+	require.NoError(t, err)
+	_, err = r.Evaluate(`[`+osvDB+`].size()`, vars)
+	require.ErrorContains(t, err, "cost limit")
+
+	// Without IO the database can't be loaded
+	r, err = runner.NewRunner()
+	require.NoError(t, err)
+	_, err = r.Evaluate(`protobom.match_vulnerabilities(sboms[0], `+osvDB+`)`, vars)
+	require.Error(t, err)
+}
+
+func TestNodeListVEX(t *testing.T) {
+	opts := runner.DefaultOptions()
+	opts.LibraryOptions = []library.OptFunc{library.WithEnableIO(true)}
+	r, err := runner.NewRunnerWithOptions(&opts)
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithPaths([]string{"testdata/github.spdx.json"}),
+	)
+	require.NoError(t, err)
+
+	const vexDoc = `protobom.load_vex("../vex/testdata/bom.openvex.json")`
+	for _, tc := range []struct {
+		name    string
+		code    string
+		mustErr bool
+		eval    func(*testing.T, ref.Val)
+	}{
+		{"status-purl", vexDoc + `.status_for(sboms[0].get_node_by_id("npm-braces-3.0.2"), "CVE-2024-4068")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "not_affected", v.Value())
+		}},
+		{"status-latest", vexDoc + `.status_for(sboms[0].get_node_by_id("npm-postcss-7.0.39"), "GHSA-7fh5-64p2-3v2j")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "affected", v.Value())
+		}},
+		{"status-none", vexDoc + `.status_for(sboms[0].get_node_by_id("npm-ansi-regex-5.0.1"), "CVE-2024-4068")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "", v.Value())
+		}},
+		{"without-not-affected", `sboms[0].without_not_affected(` + vexDoc + `, "CVE-2024-4068").get_nodes().map(n, n.id).exists(id, id == "npm-braces-3.0.2")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, false, v.Value())
+		}},
+		{"without-not-affected-nodelist", `sboms[0].get_nodes_by_purl_type("golang").without_not_affected(` + vexDoc + `, "CVE-2023-48795").get_nodes().size()`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, int64(66), v.Value())
+		}},
+		{"filter-matches", `protobom.match_vulnerabilities(sboms[0], protobom.load_osv("../osv/testdata/osv")).filter(m, ` + vexDoc + `.status_for(sboms[0].get_node_by_id(m.node_id), m.vuln_id) != "not_affected").map(m, m.vuln_id)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			ids, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"GHSA-7fh5-64p2-3v2j"}, ids)
+		}},
+		{"parse", `protobom.parse_vex('{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [{"vulnerability": {"name": "CVE-1"}, "products": [{"@id": "pkg:npm/braces"}], "status": "fixed"}]}').status_for(sboms[0].get_node_by_id("npm-braces-3.0.2"), "CVE-1")`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			require.Equal(t, "fixed", v.Value())
+		}},
+		{"statements", `dyn(` + vexDoc + `).statements.map(s, s.status)`, false, func(t *testing.T, v ref.Val) {
+			t.Helper()
+			statuses, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			require.NoError(t, err)
+			require.Equal(t, []string{"not_affected", "under_investigation", "affected", "not_affected"}, statuses)
+		}},
+		{"parse-invalid", `protobom.parse_vex("{}")`, true, nil},
+		{"load-missing", `protobom.load_vex("../vex/testdata/nope.json")`, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := r.Evaluate(tc.code, vars)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.eval(t, ret)
+		})
+	}
+}
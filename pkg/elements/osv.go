@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package elements
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+
+	"github.com/protobom/cel/pkg/osv"
+)
+
+var OSVDatabaseType = cel.ObjectType("protobom.osv.Database", traits.ReceiverType)
+
+// OSVDatabase wraps a database of OSV advisories to expose it in the CEL
+// environment
+type OSVDatabase struct {
+	*osv.Database
+}
+
+// ConvertToNative implements ref.Val.ConvertToNative.
+func (db *OSVDatabase) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	if reflect.TypeOf(db).AssignableTo(typeDesc) {
+		return db, nil
+	} else if reflect.TypeOf(db.Database).AssignableTo(typeDesc) {
+		return db.Database, nil
+	}
+
+	return nil, fmt.Errorf("type conversion error from 'OSVDatabase' to '%v'", typeDesc)
+}
+
+// ConvertToType implements ref.Val.ConvertToType.
+func (db *OSVDatabase) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case OSVDatabaseType:
+		return db
+	case types.TypeType:
+		return OSVDatabaseType
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", OSVDatabaseType, typeVal)
+}
+
+// Equal implements ref.Val.Equal.
+func (db *OSVDatabase) Equal(other ref.Val) ref.Val {
+	o, ok := other.(*OSVDatabase)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return types.Bool(db.Database == o.Database)
+}
+
+func (*OSVDatabase) Type() ref.Type {
+	return OSVDatabaseType
+}
+
+// Value implements ref.Val.Value.
+func (db *OSVDatabase) Value() any {
+	return db.Database
+}
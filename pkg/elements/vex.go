@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package elements
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+
+	"github.com/protobom/cel/pkg/vex"
+)
+
+var VEXType = cel.ObjectType("protobom.vex.Document", traits.ReceiverType, traits.IndexerType)
+
+// VEX wraps an OpenVEX document to expose it in the CEL environment
+type VEX struct {
+	*vex.Document
+}
+
+// ConvertToNative implements ref.Val.ConvertToNative.
+func (v *VEX) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	if reflect.TypeOf(v).AssignableTo(typeDesc) {
+		return v, nil
+	} else if reflect.TypeOf(v.Document).AssignableTo(typeDesc) {
+		return v.Document, nil
+	}
+
+	return nil, fmt.Errorf("type conversion error from 'VEX' to '%v'", typeDesc)
+}
+
+// ConvertToType implements ref.Val.ConvertToType.
+func (v *VEX) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case VEXType:
+		return v
+	case types.TypeType:
+		return VEXType
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", VEXType, typeVal)
+}
+
+// Equal implements ref.Val.Equal.
+func (v *VEX) Equal(other ref.Val) ref.Val {
+	o, ok := other.(*VEX)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return types.Bool(v.Document == o.Document)
+}
+
+func (*VEX) Type() ref.Type {
+	return VEXType
+}
+
+// Value implements ref.Val.Value.
+func (v *VEX) Value() any {
+	return v.Document
+}
+
+var _ traits.Indexer = (*VEX)(nil)
+
+// Get is the getter to implement the indexer trait. Statements are returned
+// as maps with the vulnerability name, its aliases, the IDs of the products
+// and the status fields.
+func (v *VEX) Get(index ref.Val) ref.Val {
+	switch k := index.Value().(type) {
+	case string:
+		switch k {
+		case "id":
+			return types.String(v.ID)
+		case "author":
+			return types.String(v.Author)
+		case "version":
+			version, err := v.Version.Int64()
+			if err != nil {
+				return types.Int(0)
+			}
+			return types.Int(version)
+		case "timestamp":
+			if v.Timestamp == nil {
+				return types.Timestamp{}
+			}
+			return types.Timestamp{Time: *v.Timestamp}
+		case "statements":
+			ret := make([]ref.Val, 0, len(v.Statements))
+			for i := range v.Statements {
+				ret = append(ret, statementToValue(&v.Statements[i]))
+			}
+			return types.NewRefValList(types.DefaultTypeAdapter, ret)
+		default:
+			return types.NewErr("no such key %v", index)
+		}
+	default:
+		return types.NewErr("no such key %v", index)
+	}
+}
+
+// statementToValue returns a VEX statement as a CEL map
+func statementToValue(s *vex.Statement) ref.Val {
+	products := make([]string, 0, len(s.Products))
+	for _, p := range s.Products {
+		products = append(products, p.ID)
+	}
+	aliases := s.Vulnerability.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	var ts time.Time
+	if s.Timestamp != nil {
+		ts = *s.Timestamp
+	}
+
+	return types.NewStringInterfaceMap(types.DefaultTypeAdapter, map[string]any{
+		"vulnerability":    s.Vulnerability.Name,
+		"aliases":          aliases,
+		"products":         products,
+		"status":           s.Status,
+		"justification":    s.Justification,
+		"impact_statement": s.ImpactStatement,
+		"action_statement": s.ActionStatement,
+		"timestamp":        ts,
+	})
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+// spdxEdgeTypes maps the SPDX 2.3 relationship type names to their
+// protobom edge type equivalents.
+var spdxEdgeTypes = map[string]sbom.Edge_Type{
+	"AMENDS":                      sbom.Edge_amends,
+	"ANCESTOR_OF":                 sbom.Edge_ancestor,
+	"BUILD_DEPENDENCY_OF":         sbom.Edge_buildDependency,
+	"BUILD_TOOL_OF":               sbom.Edge_buildTool,
+	"CONTAINS":                    sbom.Edge_contains,
+	"CONTAINED_BY":                sbom.Edge_contained_by,
+	"COPY_OF":                     sbom.Edge_copy,
+	"DATA_FILE_OF":                sbom.Edge_dataFile,
+	"DEPENDENCY_MANIFEST_OF":      sbom.Edge_dependencyManifest,
+	"DEPENDS_ON":                  sbom.Edge_dependsOn,
+	"DEPENDENCY_OF":               sbom.Edge_dependencyOf,
+	"DESCENDANT_OF":               sbom.Edge_descendant,
+	"DESCRIBES":                   sbom.Edge_describes,
+	"DESCRIBED_BY":                sbom.Edge_describedBy,
+	"DEV_DEPENDENCY_OF":           sbom.Edge_devDependency,
+	"DEV_TOOL_OF":                 sbom.Edge_devTool,
+	"DISTRIBUTION_ARTIFACT":       sbom.Edge_distributionArtifact,
+	"DOCUMENTATION_OF":            sbom.Edge_documentation,
+	"DYNAMIC_LINK":                sbom.Edge_dynamicLink,
+	"EXAMPLE_OF":                  sbom.Edge_example,
+	"EXPANDED_FROM_ARCHIVE":       sbom.Edge_expandedFromArchive,
+	"FILE_ADDED":                  sbom.Edge_fileAdded,
+	"FILE_DELETED":                sbom.Edge_fileDeleted,
+	"FILE_MODIFIED":               sbom.Edge_fileModified,
+	"GENERATES":                   sbom.Edge_generates,
+	"GENERATED_FROM":              sbom.Edge_generatedFrom,
+	"METAFILE_OF":                 sbom.Edge_metafile,
+	"OPTIONAL_COMPONENT_OF":       sbom.Edge_optionalComponent,
+	"OPTIONAL_DEPENDENCY_OF":      sbom.Edge_optionalDependency,
+	"OTHER":                       sbom.Edge_other,
+	"PACKAGE_OF":                  sbom.Edge_packages,
+	"PATCH_APPLIED":               sbom.Edge_patch,
+	"PATCH_FOR":                   sbom.Edge_patch,
+	"HAS_PREREQUISITE":            sbom.Edge_prerequisite,
+	"PREREQUISITE_FOR":            sbom.Edge_prerequisiteFor,
+	"PROVIDED_DEPENDENCY_OF":      sbom.Edge_providedDependency,
+	"REQUIREMENT_DESCRIPTION_FOR": sbom.Edge_requirementFor,
+	"RUNTIME_DEPENDENCY_OF":       sbom.Edge_runtimeDependency,
+	"SPECIFICATION_FOR":           sbom.Edge_specificationFor,
+	"STATIC_LINK":                 sbom.Edge_staticLink,
+	"TEST_OF":                     sbom.Edge_test,
+	"TEST_CASE_OF":                sbom.Edge_testCase,
+	"TEST_DEPENDENCY_OF":          sbom.Edge_testDependency,
+	"TEST_TOOL_OF":                sbom.Edge_testTool,
+	"VARIANT_OF":                  sbom.Edge_variant,
+}
+
+// edgeTypeIndex is a catalog of all the accepted spellings of the edge types
+// keyed by their normalized name.
+var edgeTypeIndex = func() map[string]sbom.Edge_Type {
+	index := map[string]sbom.Edge_Type{}
+	for name, value := range sbom.Edge_Type_value {
+		index[normalizeEdgeTypeName(name)] = sbom.Edge_Type(value)
+	}
+	for name, value := range spdxEdgeTypes {
+		index[normalizeEdgeTypeName(name)] = value
+	}
+	return index
+}()
+
+// normalizeEdgeTypeName lowercases an edge type name and strips the word
+// separators so that "DEPENDS_ON", "dependsOn" and "depends-on" all match.
+func normalizeEdgeTypeName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+}
+
+// EdgeTypeFromString returns the edge type matching name. The lookup is case
+// insensitive and accepts both the protobom (dependsOn) and SPDX (DEPENDS_ON)
+// spellings of the relationship types. An unknown name returns an error.
+func EdgeTypeFromString(name string) (sbom.Edge_Type, error) {
+	t, ok := edgeTypeIndex[normalizeEdgeTypeName(name)]
+	if !ok {
+		return sbom.Edge_UNKNOWN, fmt.Errorf("unknown relationship type %q", name)
+	}
+	return t, nil
+}
+
+// edgeTypesFromVal reads a CEL list of relationship type names and returns
+// an index of the edge types it contains.
+func edgeTypesFromVal(val ref.Val) (map[sbom.Edge_Type]struct{}, error) {
+	list, ok := val.(traits.Lister)
+	if !ok {
+		return nil, fmt.Errorf("edge types must be a list of strings, not %T", val.Value())
+	}
+
+	ret := map[sbom.Edge_Type]struct{}{}
+	for it := list.Iterator(); it.HasNext() == types.True; {
+		name, ok := it.Next().Value().(string)
+		if !ok {
+			return nil, errors.New("edge types must be a list of strings")
+		}
+		t, err := EdgeTypeFromString(name)
+		if err != nil {
+			return nil, err
+		}
+		ret[t] = struct{}{}
+	}
+	return ret, nil
+}
+
+// edgeTypesFromArg reads a single relationship type name or a list of them
+// and returns an index of the edge types.
+func edgeTypesFromArg(val ref.Val) (map[sbom.Edge_Type]struct{}, error) {
+	name, ok := val.Value().(string)
+	if !ok {
+		return edgeTypesFromVal(val)
+	}
+	t, err := EdgeTypeFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	return map[sbom.Edge_Type]struct{}{t: {}}, nil
+}
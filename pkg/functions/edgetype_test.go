@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeTypeFromString(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		sut      string
+		expected sbom.Edge_Type
+		mustErr  bool
+	}{
+		{"spdx", "DEPENDS_ON", sbom.Edge_dependsOn, false},
+		{"spdx-lower", "depends_on", sbom.Edge_dependsOn, false},
+		{"protobom", "dependsOn", sbom.Edge_dependsOn, false},
+		{"protobom-upper", "DEPENDSON", sbom.Edge_dependsOn, false},
+		{"contains", "CONTAINS", sbom.Edge_contains, false},
+		{"build-tool-spdx", "BUILD_TOOL_OF", sbom.Edge_buildTool, false},
+		{"build-tool-protobom", "buildTool", sbom.Edge_buildTool, false},
+		{"dev-dependency", "DEV_DEPENDENCY_OF", sbom.Edge_devDependency, false},
+		{"contained-by", "contained_by", sbom.Edge_contained_by, false},
+		{"prerequisite", "HAS_PREREQUISITE", sbom.Edge_prerequisite, false},
+		{"unknown", "UNKNOWN", sbom.Edge_UNKNOWN, false},
+		{"invalid", "IS_FRIENDS_WITH", sbom.Edge_UNKNOWN, true},
+		{"empty", "", sbom.Edge_UNKNOWN, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := EdgeTypeFromString(tc.sut)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+
+	// Every protobom edge type must be reachable by its own name
+	for name, value := range sbom.Edge_Type_value {
+		res, err := EdgeTypeFromString(name)
+		require.NoError(t, err)
+		require.Equal(t, sbom.Edge_Type(value), res)
+	}
+}
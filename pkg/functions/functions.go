@@ -3,16 +3,24 @@
 
 // Package functions contains the implementation of the functions that are exposed
 // to the CEL environment.
+//
+// Functions never modify their arguments, the variables passed to an
+// evaluation are shared by all the expressions evaluated with them. Results
+// are new protos, although they may reference unmodified nodes and edges of
+// their inputs.
 package functions
 
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
 	"github.com/protobom/protobom/pkg/reader"
 	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"sigs.k8s.io/release-utils/version"
 
@@ -49,16 +57,30 @@ var ToNodeList = func(lhs ref.Val) ref.Val {
 	}
 }
 
-var Addition = func(_, _ ref.Val) ref.Val {
-	return &elements.NodeList{
-		NodeList: &sbom.NodeList{},
+// Addition returns a new NodeList combining the nodes, edges and root
+// elements of two NodeLists. Nodes are deduplicated by ID and none of the
+// operands are modified.
+var Addition = func(lhs, rhs ref.Val) ref.Val {
+	nl, ok := lhs.(traits.Adder)
+	if !ok {
+		return types.NewErr("unable to add to type %T", lhs)
 	}
+	return nl.Add(rhs)
 }
 
-var AdditionOp = func(...ref.Val) ref.Val {
-	return &elements.NodeList{
+// AdditionOp folds any number of NodeLists into a new one by adding them
+// in order.
+var AdditionOp = func(vals ...ref.Val) ref.Val {
+	var ret ref.Val = &elements.NodeList{
 		NodeList: &sbom.NodeList{},
 	}
+	for _, v := range vals {
+		ret = Addition(ret, v)
+		if types.IsError(ret) {
+			return ret
+		}
+	}
+	return ret
 }
 
 // NodeByID returns a Node matching the specified ID
@@ -137,7 +159,7 @@ func getTypedNodes(element ref.Val, t sbom.Node_NodeType) (elements.NodeList, er
 		}
 
 		if v.Node.Type == t {
-			sourceNodeList.AddNode(v.Node)
+			sourceNodeList.AddNode(v.Node.Copy())
 			sourceNodeList.RootElements = append(sourceNodeList.RootElements, v.Id)
 		}
 
@@ -151,13 +173,13 @@ func getTypedNodes(element ref.Val, t sbom.Node_NodeType) (elements.NodeList, er
 	resultNodeList := elements.NodeList{
 		NodeList: &sbom.NodeList{
 			RootElements: []string{},
-			Edges:        sourceNodeList.Edges,
+			Edges:        slices.Clone(sourceNodeList.Edges),
 		},
 	}
 
 	for _, n := range sourceNodeList.Nodes {
 		if n.Type == t {
-			resultNodeList.AddNode(n)
+			resultNodeList.AddNode(n.Copy())
 		}
 	}
 
@@ -172,10 +194,12 @@ func getTypedNodes(element ref.Val, t sbom.Node_NodeType) (elements.NodeList, er
 var ToDocument = func(lhs ref.Val) ref.Val {
 	var nodelist *elements.NodeList
 	switch v := lhs.Value().(type) {
+	case *sbom.Document:
+		return &elements.Document{Document: v}
 	case *sbom.NodeList:
-		nodelist = &elements.NodeList{NodeList: v}
+		nodelist = shallowNodeListCopy(v)
 	case *elements.NodeList:
-		nodelist = v
+		nodelist = shallowNodeListCopy(v.NodeList)
 	case *elements.Node:
 		nodelist = v.ToNodeList()
 	case *sbom.Node:
@@ -245,36 +269,45 @@ var RelateNodeListAtID = func(vals ...ref.Val) ref.Val {
 	if !ok {
 		return types.NewErr("node id has to be a string")
 	}
-	// relType, this should be used below instead of Edge_dependsOn
-	_, ok = vals[3].Value().(string)
+	relType, ok := vals[3].Value().(string)
 	if !ok {
 		return types.NewErr("relationship type has has to be a string")
 	}
+	edgeType, err := EdgeTypeFromString(relType)
+	if err != nil {
+		return types.NewErr("relating nodelist: %w", err)
+	}
 
 	nodelist, ok := vals[1].(*elements.NodeList)
 	if !ok {
 		return types.NewErr("could not cast nodelist")
 	}
 
-	switch v := vals[0].Value().(type) {
-	case *sbom.Document:
-		// TODO(puerco): Lookup reltype we read from vals[3]
-		if err := v.NodeList.RelateNodeListAtID(nodelist.NodeList, id, sbom.Edge_dependsOn); err != nil {
-			return types.NewErr("relating nodelist: %w", err)
-		}
-		return &elements.Document{
-			Document: v,
-		}
-	case *sbom.NodeList:
-		if err := v.RelateNodeListAtID(nodelist.NodeList, id, sbom.Edge_dependsOn); err != nil {
-			return types.NewErr("relating nodelist: %w", err)
-		}
-		return &elements.NodeList{
-			NodeList: v,
-		}
-	default:
+	target, err := nodeListFromVal(vals[0])
+	if err != nil {
 		return types.NewErr("method unsupported on type %T", vals[0].Value())
 	}
+
+	// RelateNodeListAtID modifies the nodes and edges of both nodelists, so
+	// it operates on deep copies to leave the arguments untouched.
+	ret, ok := proto.Clone(target).(*sbom.NodeList)
+	if !ok {
+		return types.NewErr("relating nodelist: unable to copy nodelist")
+	}
+	related, ok := proto.Clone(nodelist.NodeList).(*sbom.NodeList)
+	if !ok {
+		return types.NewErr("relating nodelist: unable to copy nodelist")
+	}
+	if err := ret.RelateNodeListAtID(related, id, edgeType); err != nil {
+		return types.NewErr("relating nodelist: %w", err)
+	}
+
+	if doc, ok := vals[0].Value().(*sbom.Document); ok {
+		return documentWithNodeList(doc, ret)
+	}
+	return &elements.NodeList{
+		NodeList: ret,
+	}
 }
 
 // GetAuthors returns the document authors in a generic struct
@@ -291,20 +324,7 @@ var GetAuthors = func(lhs ref.Val) ref.Val {
 		return types.NewErr("method unsupported on type %T", lhs.Value())
 	}
 
-	reg, err := types.NewRegistry()
-	if err != nil {
-		return types.NewErrFromString(err.Error())
-	}
-
-	if metadata == nil {
-		return reg.NativeToValue([]*sbom.Person{})
-	}
-
-	if metadata.GetAuthors() == nil {
-		return reg.NativeToValue([]*sbom.Person{})
-	}
-
-	return reg.NativeToValue(metadata.GetAuthors())
+	return personList(metadata.GetAuthors())
 }
 
 var GetNodeList = func(lhs ref.Val) ref.Val {
@@ -388,16 +408,7 @@ var GetEdges = func(lhs ref.Val) ref.Val {
 var NodeGetSuppliers = func(lhs ref.Val) ref.Val {
 	switch v := lhs.Value().(type) {
 	case *sbom.Node:
-		reg, err := types.NewRegistry()
-		if err != nil {
-			return types.NewErrFromString(err.Error())
-		}
-
-		if v.GetSuppliers() == nil {
-			return reg.NativeToValue([]*sbom.Person{})
-		}
-
-		return reg.NativeToValue(v.GetSuppliers())
+		return personList(v.GetSuppliers())
 	default:
 		return types.NewErr("GetSuppliers only applies to Node")
 	}
@@ -407,17 +418,17 @@ var NodeGetSuppliers = func(lhs ref.Val) ref.Val {
 var NodeGetOriginators = func(lhs ref.Val) ref.Val {
 	switch v := lhs.Value().(type) {
 	case *sbom.Node:
-		reg, err := types.NewRegistry()
-		if err != nil {
-			return types.NewErrFromString(err.Error())
-		}
-
-		if v.GetOriginators() == nil {
-			return reg.NativeToValue([]*sbom.Person{})
-		}
-
-		return reg.NativeToValue(v.GetOriginators())
+		return personList(v.GetOriginators())
 	default:
 		return types.NewErr("GetOriginators only applies to Node")
 	}
 }
+
+// personList wraps a list of persons for the CEL runtime
+func personList(persons []*sbom.Person) ref.Val {
+	l := make([]ref.Val, 0, len(persons))
+	for _, p := range persons {
+		l = append(l, &elements.Person{Person: p})
+	}
+	return types.NewRefValList(adapter.ProtobomTypeAdapter{}, l)
+}
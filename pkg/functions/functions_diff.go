@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/adapter"
+	"github.com/protobom/cel/pkg/elements"
+)
+
+// Node fields compared when diffing SBOMs
+const (
+	DiffFieldVersion  = "version"
+	DiffFieldLicense  = "license"
+	DiffFieldHash     = "hash"
+	DiffFieldSupplier = "supplier"
+)
+
+// NodeChange is a node present in both SBOMs with different data
+type NodeChange struct {
+	// From and To are the node in the first and second SBOM
+	From *sbom.Node
+	To   *sbom.Node
+
+	// Fields lists the fields that changed (version, license, hash or
+	// supplier)
+	Fields []string
+}
+
+// DiffResult captures the differences between two NodeLists
+type DiffResult struct {
+	// Added has the nodes only found in the second NodeList
+	Added *sbom.NodeList
+
+	// Removed has the nodes only found in the first NodeList
+	Removed *sbom.NodeList
+
+	// Changed lists the matched nodes with different data
+	Changed []NodeChange
+
+	// AddedEdges and RemovedEdges have one edge for each relationship only
+	// found in the second or first NodeList. Edges use the node IDs of the
+	// NodeList they come from.
+	AddedEdges   []*sbom.Edge
+	RemovedEdges []*sbom.Edge
+}
+
+// edgeTriple is a single relationship between two nodes
+type edgeTriple struct {
+	from     string
+	edgeType sbom.Edge_Type
+	to       string
+}
+
+// Diff compares two NodeLists. Nodes are matched by their ID first and, the
+// ones left unmatched, by their package URL.
+func Diff(a, b *sbom.NodeList) *DiffResult {
+	// matches maps the IDs of the nodes in b to their match in a
+	matches := map[string]string{}
+	matched := map[string]struct{}{}
+	for _, n := range a.GetNodes() {
+		if bn := b.GetNodeByID(n.GetId()); bn != nil {
+			matches[bn.GetId()] = n.GetId()
+			matched[n.GetId()] = struct{}{}
+		}
+	}
+
+	purls := map[sbom.PackageURL]string{}
+	for _, n := range a.GetNodes() {
+		if _, ok := matched[n.GetId()]; ok || n.Purl() == "" {
+			continue
+		}
+		if _, ok := purls[n.Purl()]; !ok {
+			purls[n.Purl()] = n.GetId()
+		}
+	}
+	for _, n := range b.GetNodes() {
+		if _, ok := matches[n.GetId()]; ok || n.Purl() == "" {
+			continue
+		}
+		if id, ok := purls[n.Purl()]; ok {
+			matches[n.GetId()] = id
+			matched[id] = struct{}{}
+			delete(purls, n.Purl())
+		}
+	}
+
+	ret := &DiffResult{
+		Added: filterNodeList(b, func(n *sbom.Node) bool {
+			_, ok := matches[n.GetId()]
+			return !ok
+		}).NodeList,
+		Removed: filterNodeList(a, func(n *sbom.Node) bool {
+			_, ok := matched[n.GetId()]
+			return !ok
+		}).NodeList,
+		Changed:      []NodeChange{},
+		AddedEdges:   []*sbom.Edge{},
+		RemovedEdges: []*sbom.Edge{},
+	}
+
+	for _, n := range b.GetNodes() {
+		id, ok := matches[n.GetId()]
+		if !ok {
+			continue
+		}
+		from := a.GetNodeByID(id)
+		if fields := changedFields(from, n); len(fields) > 0 {
+			ret.Changed = append(ret.Changed, NodeChange{From: from, To: n, Fields: fields})
+		}
+	}
+
+	// Translate the edges of b to the IDs in a to compare them. IDs of
+	// unmatched nodes are kept but flagged so they never collide with a.
+	toA := func(id string) string {
+		if aid, ok := matches[id]; ok {
+			return aid
+		}
+		return "\x00" + id
+	}
+
+	aTriples := edgeTriples(a, func(id string) string { return id })
+	bTriples := edgeTriples(b, toA)
+	for _, t := range aTriples.list {
+		if _, ok := bTriples.index[t.key]; !ok {
+			ret.RemovedEdges = append(ret.RemovedEdges, t.edge)
+		}
+	}
+	for _, t := range bTriples.list {
+		if _, ok := aTriples.index[t.key]; !ok {
+			ret.AddedEdges = append(ret.AddedEdges, t.edge)
+		}
+	}
+	return ret
+}
+
+// indexedTriple is a relationship keyed in a common ID space along with the
+// single edge representing it in its original NodeList
+type indexedTriple struct {
+	key  edgeTriple
+	edge *sbom.Edge
+}
+
+// tripleSet is the ordered list of relationships in a NodeList
+type tripleSet struct {
+	list  []indexedTriple
+	index map[edgeTriple]struct{}
+}
+
+// edgeTriples splits the edges of a NodeList into single relationships,
+// keying them with the IDs returned by key.
+func edgeTriples(nl *sbom.NodeList, key func(string) string) *tripleSet {
+	ret := &tripleSet{index: map[edgeTriple]struct{}{}}
+	for _, e := range nl.GetEdges() {
+		for _, to := range e.GetTo() {
+			t := edgeTriple{from: key(e.GetFrom()), edgeType: e.GetType(), to: key(to)}
+			if _, ok := ret.index[t]; ok {
+				continue
+			}
+			ret.index[t] = struct{}{}
+			ret.list = append(ret.list, indexedTriple{
+				key:  t,
+				edge: &sbom.Edge{Type: e.GetType(), From: e.GetFrom(), To: []string{to}},
+			})
+		}
+	}
+	return ret
+}
+
+// changedFields returns the compared fields that differ between two nodes
+func changedFields(a, b *sbom.Node) []string {
+	ret := []string{}
+	if a.GetVersion() != b.GetVersion() {
+		ret = append(ret, DiffFieldVersion)
+	}
+
+	if a.GetLicenseConcluded() != b.GetLicenseConcluded() ||
+		!slices.Equal(sortedCopy(a.GetLicenses()), sortedCopy(b.GetLicenses())) {
+		ret = append(ret, DiffFieldLicense)
+	}
+
+	if !maps.Equal(a.GetHashes(), b.GetHashes()) {
+		ret = append(ret, DiffFieldHash)
+	}
+
+	if !slices.Equal(personKeys(a.GetSuppliers()), personKeys(b.GetSuppliers())) {
+		ret = append(ret, DiffFieldSupplier)
+	}
+	return ret
+}
+
+// sortedCopy returns a sorted copy of a string slice
+func sortedCopy(s []string) []string {
+	ret := slices.Clone(s)
+	slices.Sort(ret)
+	return ret
+}
+
+// personKeys returns the sorted names and emails of a list of persons
+func personKeys(persons []*sbom.Person) []string {
+	ret := make([]string, 0, len(persons))
+	for _, p := range persons {
+		ret = append(ret, p.GetName()+"\x00"+p.GetEmail())
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// ToValue returns the diff result as a CEL map
+func (d *DiffResult) ToValue() ref.Val {
+	changed := make([]ref.Val, 0, len(d.Changed))
+	for _, c := range d.Changed {
+		changed = append(changed, types.NewStringInterfaceMap(adapter.ProtobomTypeAdapter{}, map[string]any{
+			"id":     c.To.GetId(),
+			"name":   c.To.GetName(),
+			"from":   c.From,
+			"to":     c.To,
+			"fields": c.Fields,
+		}))
+	}
+
+	return types.NewStringInterfaceMap(adapter.ProtobomTypeAdapter{}, map[string]any{
+		"added":         d.Added,
+		"removed":       d.Removed,
+		"changed":       types.NewRefValList(adapter.ProtobomTypeAdapter{}, changed),
+		"added_edges":   edgeValues(d.AddedEdges),
+		"removed_edges": edgeValues(d.RemovedEdges),
+	})
+}
+
+// edgeValues wraps a list of edges for the CEL runtime
+func edgeValues(edges []*sbom.Edge) ref.Val {
+	vals := make([]ref.Val, 0, len(edges))
+	for _, e := range edges {
+		vals = append(vals, &elements.Edge{Edge: e})
+	}
+	return types.NewRefValList(adapter.ProtobomTypeAdapter{}, vals)
+}
+
+// DiffBinding compares two Documents or NodeLists and returns a map with
+// the added, removed and changed nodes and the added and removed edges:
+//
+//	protobom.diff(sboms[0], sboms[1]).added.to_document()
+var DiffBinding = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 3 {
+		return types.NewErr("diff takes two arguments")
+	}
+	a, err := nodeListFromVal(vals[1])
+	if err != nil {
+		return types.NewErr("diff: %w", err)
+	}
+	b, err := nodeListFromVal(vals[2])
+	if err != nil {
+		return types.NewErr("diff: %w", err)
+	}
+	return Diff(a, b).ToValue()
+}
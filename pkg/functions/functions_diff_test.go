@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	// a: root -> lib -> old, root -> tool
+	a := &sbom.NodeList{
+		Nodes: []*sbom.Node{
+			{Id: "root", Name: "root", Version: "1.0.0"},
+			purlNode("lib", "pkg:golang/lib@1.0.0"),
+			purlNode("old", "pkg:golang/old@1.0.0"),
+			purlNode("tool", "pkg:golang/tool@1.0.0"),
+		},
+		Edges: []*sbom.Edge{
+			{Type: sbom.Edge_dependsOn, From: "root", To: []string{"lib", "tool"}},
+			{Type: sbom.Edge_dependsOn, From: "lib", To: []string{"old"}},
+		},
+		RootElements: []string{"root"},
+	}
+	a.Nodes[1].Hashes = map[int32]string{int32(sbom.HashAlgorithm_SHA256): "aaa"}
+	a.Nodes[1].LicenseConcluded = "MIT"
+
+	// b: the root was bumped, lib has a new hash, license and supplier, old
+	// was replaced by new and tool was generated with a different ID.
+	b := &sbom.NodeList{
+		Nodes: []*sbom.Node{
+			{Id: "root", Name: "root", Version: "1.1.0"},
+			purlNode("lib", "pkg:golang/lib@1.0.0"),
+			purlNode("new", "pkg:golang/new@1.0.0"),
+			purlNode("tool-1", "pkg:golang/tool@1.0.0"),
+		},
+		Edges: []*sbom.Edge{
+			{Type: sbom.Edge_dependsOn, From: "root", To: []string{"lib", "tool-1"}},
+			{Type: sbom.Edge_dependsOn, From: "lib", To: []string{"new"}},
+		},
+		RootElements: []string{"root"},
+	}
+	b.Nodes[1].Hashes = map[int32]string{int32(sbom.HashAlgorithm_SHA256): "bbb"}
+	b.Nodes[1].LicenseConcluded = "Apache-2.0"
+	b.Nodes[1].Suppliers = []*sbom.Person{{Name: "ACME"}}
+
+	d := Diff(a, b)
+
+	require.Len(t, d.Added.GetNodes(), 1)
+	require.Equal(t, "new", d.Added.GetNodes()[0].GetId())
+	require.Equal(t, []string{"new"}, d.Added.GetRootElements())
+	require.Len(t, d.Removed.GetNodes(), 1)
+	require.Equal(t, "old", d.Removed.GetNodes()[0].GetId())
+
+	require.Len(t, d.Changed, 2)
+	require.Equal(t, "root", d.Changed[0].To.GetId())
+	require.Equal(t, []string{DiffFieldVersion}, d.Changed[0].Fields)
+	require.Equal(t, "lib", d.Changed[1].To.GetId())
+	require.Equal(t, []string{DiffFieldLicense, DiffFieldHash, DiffFieldSupplier}, d.Changed[1].Fields)
+
+	// tool and tool-1 are matched by purl so their edges are equivalent
+	require.Equal(t, []*sbom.Edge{{Type: sbom.Edge_dependsOn, From: "lib", To: []string{"new"}}}, d.AddedEdges)
+	require.Equal(t, []*sbom.Edge{{Type: sbom.Edge_dependsOn, From: "lib", To: []string{"old"}}}, d.RemovedEdges)
+
+	// Comparing a NodeList to itself returns no differences
+	d = Diff(a, a)
+	require.Empty(t, d.Added.GetNodes())
+	require.Empty(t, d.Removed.GetNodes())
+	require.Empty(t, d.Changed)
+	require.Empty(t, d.AddedEdges)
+	require.Empty(t, d.RemovedEdges)
+
+	// The inputs are not modified
+	require.Len(t, a.GetNodes(), 4)
+	require.Equal(t, []string{"lib", "tool"}, a.GetEdges()[0].GetTo())
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/package-url/packageurl-go"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/protobom/cel/pkg/elements"
+	"github.com/protobom/cel/pkg/license"
+)
+
+// editNode clones the node in val and applies edit to the copy. The
+// original node is never modified.
+func editNode(name string, val ref.Val, edit func(*sbom.Node) error) ref.Val {
+	n, ok := val.Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("%s() only applies to Node", name)
+	}
+
+	clone, ok := proto.Clone(n).(*sbom.Node)
+	if !ok {
+		return types.NewErr("%s: unable to clone node", name)
+	}
+	if err := edit(clone); err != nil {
+		return types.NewErr("%s: %w", name, err)
+	}
+	return &elements.Node{Node: clone}
+}
+
+// stringArgs reads the string arguments of a function call
+func stringArgs(vals ...ref.Val) ([]string, error) {
+	ret := make([]string, 0, len(vals))
+	for _, v := range vals {
+		s, ok := v.Value().(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string argument, got %T", v.Value())
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+// NodeWithProperty returns a copy of the node with a property set. Any
+// properties with the same name are replaced:
+//
+//	node.with_property("reviewed", "true")
+var NodeWithProperty = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 3 {
+		return types.NewErr("with_property takes two arguments")
+	}
+	args, err := stringArgs(vals[1:]...)
+	if err != nil {
+		return types.NewErr("with_property: %w", err)
+	}
+
+	return editNode("with_property", vals[0], func(n *sbom.Node) error {
+		n.Properties = slices.DeleteFunc(n.Properties, func(p *sbom.Property) bool {
+			return p.GetName() == args[0]
+		})
+		n.Properties = append(n.Properties, &sbom.Property{Name: args[0], Data: args[1]})
+		return nil
+	})
+}
+
+// NodeWithSupplier returns a copy of the node with its suppliers replaced by
+// a single person:
+//
+//	node.with_supplier("ACME Corp", "sbom@acme.example")
+var NodeWithSupplier = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 3 {
+		return types.NewErr("with_supplier takes two arguments")
+	}
+	args, err := stringArgs(vals[1:]...)
+	if err != nil {
+		return types.NewErr("with_supplier: %w", err)
+	}
+
+	return editNode("with_supplier", vals[0], func(n *sbom.Node) error {
+		n.Suppliers = []*sbom.Person{{Name: args[0], Email: args[1]}}
+		return nil
+	})
+}
+
+// NodeWithLicenseConcluded returns a copy of the node with a new concluded
+// license. The expression must be a valid SPDX license expression, NONE or
+// NOASSERTION:
+//
+//	node.with_license_concluded("MIT OR Apache-2.0")
+var NodeWithLicenseConcluded = func(lhs, rhs ref.Val) ref.Val {
+	expr, ok := rhs.Value().(string)
+	if !ok {
+		return types.NewErr("with_license_concluded: license expression must be a string")
+	}
+
+	return editNode("with_license_concluded", lhs, func(n *sbom.Node) error {
+		if !isNoLicense(expr) {
+			if _, err := license.Parse(expr); err != nil {
+				return err
+			}
+		}
+		n.LicenseConcluded = expr
+		return nil
+	})
+}
+
+// NodeWithVersion returns a copy of the node with a new version. If the node
+// has a package URL, its version is updated too:
+//
+//	node.with_version("1.2.3")
+var NodeWithVersion = func(lhs, rhs ref.Val) ref.Val {
+	version, ok := rhs.Value().(string)
+	if !ok {
+		return types.NewErr("with_version: version must be a string")
+	}
+
+	return editNode("with_version", lhs, func(n *sbom.Node) error {
+		n.Version = version
+		if n.Purl() == "" {
+			return nil
+		}
+
+		purl, err := packageurl.FromString(string(n.Purl()))
+		if err != nil {
+			return fmt.Errorf("parsing purl of node %q: %w", n.GetId(), err)
+		}
+		purl.Version = version
+		n.Identifiers[int32(sbom.SoftwareIdentifierType_PURL)] = purl.ToString()
+		return nil
+	})
+}
+
+// ReplaceNode returns a copy of a NodeList or Document where the node with
+// the same ID as the argument is replaced by it. The edges and the rest of
+// the nodes are shared with the original, which is left untouched:
+//
+//	sbom.replace_node(sbom.get_node_by_id("pkg").with_version("1.0.1"))
+var ReplaceNode = func(lhs, rhs ref.Val) ref.Val {
+	node, ok := rhs.Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("replace_node: argument must be a Node")
+	}
+
+	nl, err := nodeListFromVal(lhs)
+	if err != nil {
+		return types.NewErr("replace_node: %w", err)
+	}
+
+	i := slices.IndexFunc(nl.GetNodes(), func(n *sbom.Node) bool {
+		return n.GetId() == node.GetId()
+	})
+	if i == -1 {
+		return types.NewErr("replace_node: node %q not found", node.GetId())
+	}
+
+	ret := shallowNodeListCopy(nl)
+	ret.Nodes[i] = node
+
+	if doc, ok := lhs.Value().(*sbom.Document); ok {
+		return documentWithNodeList(doc, ret.NodeList)
+	}
+	return ret
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+func editTestNode() *sbom.Node {
+	return &sbom.Node{
+		Id:      "pkg",
+		Name:    "pkg",
+		Version: "1.0.0",
+		Identifiers: map[int32]string{
+			int32(sbom.SoftwareIdentifierType_PURL): "pkg:golang/example.com/pkg@1.0.0",
+		},
+		Properties: []*sbom.Property{{Name: "reviewed", Data: "false"}, {Name: "other", Data: "x"}},
+		Suppliers:  []*sbom.Person{{Name: "Someone"}},
+	}
+}
+
+func TestNodeEdits(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		edit    func(ref.Val) ref.Val
+		mustErr bool
+		check   func(*testing.T, *sbom.Node)
+	}{
+		{"property", func(n ref.Val) ref.Val {
+			return NodeWithProperty(n, types.String("reviewed"), types.String("true"))
+		}, false, func(t *testing.T, n *sbom.Node) {
+			t.Helper()
+			require.Len(t, n.GetProperties(), 2)
+			require.Equal(t, "other", n.GetProperties()[0].GetName())
+			require.Equal(t, "true", n.GetProperties()[1].GetData())
+		}},
+		{"supplier", func(n ref.Val) ref.Val {
+			return NodeWithSupplier(n, types.String("ACME"), types.String("sbom@acme.example"))
+		}, false, func(t *testing.T, n *sbom.Node) {
+			t.Helper()
+			require.Len(t, n.GetSuppliers(), 1)
+			require.Equal(t, "ACME", n.GetSuppliers()[0].GetName())
+			require.Equal(t, "sbom@acme.example", n.GetSuppliers()[0].GetEmail())
+		}},
+		{"license", func(n ref.Val) ref.Val {
+			return NodeWithLicenseConcluded(n, types.String("MIT OR Apache-2.0"))
+		}, false, func(t *testing.T, n *sbom.Node) {
+			t.Helper()
+			require.Equal(t, "MIT OR Apache-2.0", n.GetLicenseConcluded())
+		}},
+		{"license-noassertion", func(n ref.Val) ref.Val {
+			return NodeWithLicenseConcluded(n, types.String("NOASSERTION"))
+		}, false, func(t *testing.T, n *sbom.Node) {
+			t.Helper()
+			require.Equal(t, "NOASSERTION", n.GetLicenseConcluded())
+		}},
+		{"license-invalid", func(n ref.Val) ref.Val {
+			return NodeWithLicenseConcluded(n, types.String("MIT AND"))
+		}, true, nil},
+		{"version", func(n ref.Val) ref.Val {
+			return NodeWithVersion(n, types.String("1.1.0"))
+		}, false, func(t *testing.T, n *sbom.Node) {
+			t.Helper()
+			require.Equal(t, "1.1.0", n.GetVersion())
+			require.Equal(t, sbom.PackageURL("pkg:golang/example.com/pkg@1.1.0"), n.Purl())
+		}},
+		{"wrong-args", func(n ref.Val) ref.Val {
+			return NodeWithProperty(n, types.String("reviewed"))
+		}, true, nil},
+		{"not-a-node", func(ref.Val) ref.Val {
+			return NodeWithVersion(&elements.NodeList{NodeList: &sbom.NodeList{}}, types.String("1.1.0"))
+		}, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			original := editTestNode()
+			ret := tc.edit(&elements.Node{Node: original})
+			if tc.mustErr {
+				require.True(t, types.IsError(ret))
+				return
+			}
+			n, ok := ret.Value().(*sbom.Node)
+			require.True(t, ok)
+			tc.check(t, n)
+
+			// The original node must not change
+			require.True(t, editTestNode().Equal(original))
+		})
+	}
+}
+
+func TestReplaceNode(t *testing.T) {
+	nl := &sbom.NodeList{
+		Nodes:        []*sbom.Node{{Id: "root"}, editTestNode()},
+		Edges:        []*sbom.Edge{{Type: sbom.Edge_dependsOn, From: "root", To: []string{"pkg"}}},
+		RootElements: []string{"root"},
+	}
+	edited := NodeWithVersion(&elements.Node{Node: nl.GetNodeByID("pkg")}, types.String("2.0.0"))
+
+	ret := ReplaceNode(&elements.NodeList{NodeList: nl}, edited)
+	res, ok := ret.Value().(*sbom.NodeList)
+	require.True(t, ok, ret)
+	require.Equal(t, "2.0.0", res.GetNodeByID("pkg").GetVersion())
+	require.Equal(t, "1.0.0", nl.GetNodeByID("pkg").GetVersion())
+	require.Len(t, res.GetEdges(), 1)
+	require.Equal(t, []string{"root"}, res.GetRootElements())
+
+	ret = ReplaceNode(&elements.Document{Document: &sbom.Document{Metadata: &sbom.Metadata{Id: "doc"}, NodeList: nl}}, edited)
+	doc, ok := ret.Value().(*sbom.Document)
+	require.True(t, ok, ret)
+	require.Equal(t, "doc", doc.GetMetadata().GetId())
+	require.Equal(t, "2.0.0", doc.GetNodeList().GetNodeByID("pkg").GetVersion())
+
+	require.True(t, types.IsError(ReplaceNode(&elements.NodeList{NodeList: nl}, &elements.Node{Node: &sbom.Node{Id: "missing"}})))
+}
@@ -0,0 +1,647 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/adapter"
+	"github.com/protobom/cel/pkg/elements"
+)
+
+// graphHop is a single step in the nodelist graph
+type graphHop struct {
+	id       string
+	edgeType sbom.Edge_Type
+}
+
+// graphIndex indexes the edges of a nodelist by their origin
+type graphIndex map[string][]graphHop
+
+// newGraphIndex walks the edges of the nodelist and indexes the hops from
+// each node. If edgeTypes is not empty, only edges of those types are
+// indexed.
+func newGraphIndex(nl *sbom.NodeList, edgeTypes map[sbom.Edge_Type]struct{}) graphIndex {
+	idx := graphIndex{}
+	for _, e := range nl.GetEdges() {
+		if len(edgeTypes) > 0 {
+			if _, ok := edgeTypes[e.GetType()]; !ok {
+				continue
+			}
+		}
+		for _, to := range e.GetTo() {
+			idx[e.GetFrom()] = append(idx[e.GetFrom()], graphHop{id: to, edgeType: e.GetType()})
+		}
+	}
+	return idx
+}
+
+// newReverseGraphIndex works like newGraphIndex but indexes the hops
+// pointing to each node, the hop ids are the edge origins.
+func newReverseGraphIndex(nl *sbom.NodeList, edgeTypes map[sbom.Edge_Type]struct{}) graphIndex {
+	idx := graphIndex{}
+	for _, e := range nl.GetEdges() {
+		if len(edgeTypes) > 0 {
+			if _, ok := edgeTypes[e.GetType()]; !ok {
+				continue
+			}
+		}
+		for _, to := range e.GetTo() {
+			idx[to] = append(idx[to], graphHop{id: e.GetFrom(), edgeType: e.GetType()})
+		}
+	}
+	return idx
+}
+
+// indexNodes returns a map of the nodelist nodes keyed by their ID
+func indexNodes(nl *sbom.NodeList) map[string]*sbom.Node {
+	idx := make(map[string]*sbom.Node, len(nl.GetNodes()))
+	for _, n := range nl.GetNodes() {
+		idx[n.GetId()] = n
+	}
+	return idx
+}
+
+// pathFragment returns a new NodeList holding copies of the nodes in a path
+// and the edges that connect them. The first node in the path is the root.
+func pathFragment(nodes map[string]*sbom.Node, start string, hops []graphHop) *elements.NodeList {
+	nl := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{nodes[start].Copy()},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{start},
+		},
+	}
+
+	from := start
+	for _, hop := range hops {
+		nl.AddNode(nodes[hop.id].Copy())
+		nl.AddEdge(from, hop.edgeType, []string{hop.id})
+		from = hop.id
+	}
+	return nl
+}
+
+// allPaths returns all the simple paths from one node to another of up to
+// maxDepth hops, in the order the edges are defined in the graph.
+func allPaths(graph graphIndex, nodes map[string]*sbom.Node, from, to string, maxDepth int, interrupted InterruptCheck) ([][]graphHop, error) {
+	paths := [][]graphHop{}
+	if from == to {
+		return append(paths, []graphHop{}), nil
+	}
+
+	onPath := map[string]struct{}{from: {}}
+	current := []graphHop{}
+
+	var err error
+	var walk func(id string)
+	walk = func(id string) {
+		if len(current) >= maxDepth {
+			return
+		}
+		for _, hop := range graph[id] {
+			if err != nil {
+				return
+			}
+			if interrupted() {
+				err = ErrInterrupted
+				return
+			}
+			if _, ok := onPath[hop.id]; ok {
+				continue
+			}
+			if _, ok := nodes[hop.id]; !ok {
+				continue
+			}
+
+			current = append(current, hop)
+			if hop.id == to {
+				paths = append(paths, append([]graphHop{}, current...))
+			} else {
+				onPath[hop.id] = struct{}{}
+				walk(hop.id)
+				delete(onPath, hop.id)
+			}
+			current = current[:len(current)-1]
+		}
+	}
+	walk(from)
+
+	return paths, err
+}
+
+// shortestPath runs a breadth first search to find the shortest path between
+// two nodes. It returns false if the nodes are not connected.
+func shortestPath(graph graphIndex, nodes map[string]*sbom.Node, from, to string, interrupted InterruptCheck) ([]graphHop, bool, error) {
+	if from == to {
+		return []graphHop{}, true, nil
+	}
+
+	// previous records the hop used to reach each visited node
+	previous := map[string]graphHop{}
+	origin := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		if interrupted() {
+			return nil, false, ErrInterrupted
+		}
+		id := queue[0]
+		queue = queue[1:]
+		for _, hop := range graph[id] {
+			if _, ok := origin[hop.id]; ok {
+				continue
+			}
+			if _, ok := nodes[hop.id]; !ok {
+				continue
+			}
+			origin[hop.id] = id
+			previous[hop.id] = hop
+			if hop.id != to {
+				queue = append(queue, hop.id)
+				continue
+			}
+
+			// Found it, rebuild the path backwards
+			path := []graphHop{}
+			for cur := to; cur != from; cur = origin[cur] {
+				path = append([]graphHop{previous[cur]}, path...)
+			}
+			return path, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// NodePaths returns a list of NodeLists, each holding one of the paths that
+// connect two nodes of up to max_depth hops. An optional list of relationship
+// types limits the edges that are traversed.
+//
+//	nl.get_paths(from_id, to_id, max_depth)
+//	nl.get_paths(from_id, to_id, max_depth, "DEPENDS_ON")
+//	nl.get_paths(from_id, to_id, max_depth, ["DEPENDS_ON", "CONTAINS"])
+func NodePaths(vals ...ref.Val) ref.Val {
+	return nodePaths(neverInterrupted, vals...)
+}
+
+// nodePaths implements NodePaths checking for interrupts
+func nodePaths(interrupted InterruptCheck, vals ...ref.Val) ref.Val {
+	if len(vals) != 4 && len(vals) != 5 {
+		return types.NewErr("incorrect number of params")
+	}
+	nl, err := nodeListFromVal(vals[0])
+	if err != nil {
+		return types.NewErr("get_paths: %w", err)
+	}
+	from, ok := vals[1].Value().(string)
+	if !ok {
+		return types.NewErr("from node id must be a string, not %T", vals[1].Value())
+	}
+	to, ok := vals[2].Value().(string)
+	if !ok {
+		return types.NewErr("to node id must be a string, not %T", vals[2].Value())
+	}
+	maxDepth, ok := vals[3].Value().(int64)
+	if !ok {
+		return types.NewErr("maxDepth must be an int, not %T", vals[3].Value())
+	}
+	if maxDepth < 1 {
+		return types.NewErr("maxDepth must be greater than zero")
+	}
+
+	var edgeTypes map[sbom.Edge_Type]struct{}
+	if len(vals) == 5 {
+		edgeTypes, err = edgeTypesFromArg(vals[4])
+		if err != nil {
+			return types.NewErr("get_paths: %w", err)
+		}
+	}
+
+	nodes := indexNodes(nl)
+	l := []ref.Val{}
+	if _, ok := nodes[from]; ok {
+		if _, ok := nodes[to]; ok {
+			paths, err := allPaths(newGraphIndex(nl, edgeTypes), nodes, from, to, int(maxDepth), interrupted)
+			if err != nil {
+				return types.NewErr("get_paths: %w", err)
+			}
+			for _, path := range paths {
+				l = append(l, pathFragment(nodes, from, path))
+			}
+		}
+	}
+
+	return types.NewRefValList(adapter.ProtobomTypeAdapter{}, l)
+}
+
+// ShortestPath returns a NodeList holding the nodes and edges in the shortest
+// path between two nodes. If the nodes are not connected, the NodeList is
+// empty. An optional list of relationship types limits the edges that are
+// traversed.
+//
+//	nl.get_shortest_path(from_id, to_id)
+//	nl.get_shortest_path(from_id, to_id, "DEPENDS_ON")
+//	nl.get_shortest_path(from_id, to_id, ["DEPENDS_ON", "CONTAINS"])
+func ShortestPath(vals ...ref.Val) ref.Val {
+	return getShortestPath(neverInterrupted, vals...)
+}
+
+// getShortestPath implements ShortestPath checking for interrupts
+func getShortestPath(interrupted InterruptCheck, vals ...ref.Val) ref.Val {
+	if len(vals) != 3 && len(vals) != 4 {
+		return types.NewErr("incorrect number of params")
+	}
+	nl, err := nodeListFromVal(vals[0])
+	if err != nil {
+		return types.NewErr("get_shortest_path: %w", err)
+	}
+	from, ok := vals[1].Value().(string)
+	if !ok {
+		return types.NewErr("from node id must be a string, not %T", vals[1].Value())
+	}
+	to, ok := vals[2].Value().(string)
+	if !ok {
+		return types.NewErr("to node id must be a string, not %T", vals[2].Value())
+	}
+
+	var edgeTypes map[sbom.Edge_Type]struct{}
+	if len(vals) == 4 {
+		edgeTypes, err = edgeTypesFromArg(vals[3])
+		if err != nil {
+			return types.NewErr("get_shortest_path: %w", err)
+		}
+	}
+
+	empty := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{},
+		},
+	}
+
+	nodes := indexNodes(nl)
+	if _, ok := nodes[from]; !ok {
+		return empty
+	}
+	if _, ok := nodes[to]; !ok {
+		return empty
+	}
+
+	path, found, err := shortestPath(newGraphIndex(nl, edgeTypes), nodes, from, to, interrupted)
+	if err != nil {
+		return types.NewErr("get_shortest_path: %w", err)
+	}
+	if !found {
+		return empty
+	}
+	return pathFragment(nodes, from, path)
+}
+
+// NodeAncestors traverses the NodeList graph backwards starting at the node
+// specified by id and returns a new NodeList with all the nodes that reach it
+// in up to max_depth hops and the edges connecting them. An optional list of
+// relationship types limits the edges that are traversed. If the node is not
+// found, the NodeList will be empty.
+//
+//	nl.get_node_ancestors(id, max_depth)
+//	nl.get_node_ancestors(id, max_depth, "DEPENDS_ON")
+//	nl.get_node_ancestors(id, max_depth, ["DEPENDS_ON", "CONTAINS"])
+func NodeAncestors(vals ...ref.Val) ref.Val {
+	return nodeAncestors(neverInterrupted, vals...)
+}
+
+// nodeAncestors implements NodeAncestors checking for interrupts
+func nodeAncestors(interrupted InterruptCheck, vals ...ref.Val) ref.Val {
+	if len(vals) != 3 && len(vals) != 4 {
+		return types.NewErr("incorrect number of params")
+	}
+	nl, err := nodeListFromVal(vals[0])
+	if err != nil {
+		return types.NewErr("get_node_ancestors: %w", err)
+	}
+	id, ok := vals[1].Value().(string)
+	if !ok {
+		return types.NewErr("node id must be a string, not %T", vals[1].Value())
+	}
+	maxDepth, ok := vals[2].Value().(int64)
+	if !ok {
+		return types.NewErr("maxDepth must be an int, not %T", vals[2].Value())
+	}
+
+	var edgeTypes map[sbom.Edge_Type]struct{}
+	if len(vals) == 4 {
+		edgeTypes, err = edgeTypesFromArg(vals[3])
+		if err != nil {
+			return types.NewErr("get_node_ancestors: %w", err)
+		}
+	}
+
+	ret := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{},
+		},
+	}
+
+	nodes := indexNodes(nl)
+	if _, ok := nodes[id]; !ok {
+		return ret
+	}
+
+	graph := newReverseGraphIndex(nl, edgeTypes)
+	seen := map[string]struct{}{id: {}}
+	ret.AddNode(nodes[id].Copy())
+
+	level := []string{id}
+	for depth := int64(0); depth < maxDepth && len(level) > 0; depth++ {
+		next := []string{}
+		for _, child := range level {
+			if interrupted() {
+				return types.NewErr("get_node_ancestors: %w", ErrInterrupted)
+			}
+			for _, hop := range graph[child] {
+				if _, ok := nodes[hop.id]; !ok {
+					continue
+				}
+				ret.AddEdge(hop.id, hop.edgeType, []string{child})
+				if _, ok := seen[hop.id]; ok {
+					continue
+				}
+				seen[hop.id] = struct{}{}
+				ret.AddNode(nodes[hop.id].Copy())
+				next = append(next, hop.id)
+			}
+		}
+		level = next
+	}
+
+	reconnectOrphanNodes(ret)
+	return ret
+}
+
+// EdgesByType returns the edges of a NodeList with one of the relationship
+// types in the argument, which can be a single type or a list:
+//
+//	nl.get_edges_by_type("CONTAINS")
+//	nl.get_edges_by_type(["DEPENDS_ON", "RUNTIME_DEPENDENCY_OF"])
+var EdgesByType = func(lhs, rhs ref.Val) ref.Val {
+	nl, err := nodeListFromVal(lhs)
+	if err != nil {
+		return types.NewErr("get_edges_by_type: %w", err)
+	}
+	edgeTypes, err := edgeTypesFromArg(rhs)
+	if err != nil {
+		return types.NewErr("get_edges_by_type: %w", err)
+	}
+
+	edges := []*sbom.Edge{}
+	for _, e := range nl.GetEdges() {
+		if _, ok := edgeTypes[e.GetType()]; ok {
+			edges = append(edges, e)
+		}
+	}
+	return edgeValues(edges)
+}
+
+// RelatedNodes returns a NodeList with the nodes directly connected to a
+// node through edges of the relationship types in the last argument. Edges
+// are followed in both directions and the node itself is not included:
+//
+//	nl.get_related("my-app", "BUILD_TOOL_OF")
+var RelatedNodes = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 3 {
+		return types.NewErr("incorrect number of params")
+	}
+	nl, err := nodeListFromVal(vals[0])
+	if err != nil {
+		return types.NewErr("get_related: %w", err)
+	}
+	id, ok := vals[1].Value().(string)
+	if !ok {
+		return types.NewErr("node id must be a string, not %T", vals[1].Value())
+	}
+	edgeTypes, err := edgeTypesFromArg(vals[2])
+	if err != nil {
+		return types.NewErr("get_related: %w", err)
+	}
+
+	related := map[string]struct{}{}
+	for _, hop := range newGraphIndex(nl, edgeTypes)[id] {
+		related[hop.id] = struct{}{}
+	}
+	for _, hop := range newReverseGraphIndex(nl, edgeTypes)[id] {
+		related[hop.id] = struct{}{}
+	}
+	delete(related, id)
+
+	return filterNodeList(nl, func(n *sbom.Node) bool {
+		_, ok := related[n.GetId()]
+		return ok
+	})
+}
+
+// nodeDepths walks the NodeList graph from its root elements and returns the
+// depth of each reachable node, the number of hops in the shortest path from
+// a root. Root elements are at depth zero. If edgeTypes is not empty, only
+// edges of those types are traversed.
+func nodeDepths(nl *sbom.NodeList, edgeTypes map[sbom.Edge_Type]struct{}, interrupted InterruptCheck) (map[string]int64, error) {
+	nodes := indexNodes(nl)
+	graph := newGraphIndex(nl, edgeTypes)
+	depths := map[string]int64{}
+
+	level := []string{}
+	for _, id := range nl.GetRootElements() {
+		if _, ok := nodes[id]; !ok {
+			continue
+		}
+		if _, ok := depths[id]; ok {
+			continue
+		}
+		depths[id] = 0
+		level = append(level, id)
+	}
+
+	for depth := int64(1); len(level) > 0; depth++ {
+		next := []string{}
+		for _, id := range level {
+			if interrupted() {
+				return nil, ErrInterrupted
+			}
+			for _, hop := range graph[id] {
+				if _, ok := nodes[hop.id]; !ok {
+					continue
+				}
+				if _, ok := depths[hop.id]; ok {
+					continue
+				}
+				depths[hop.id] = depth
+				next = append(next, hop.id)
+			}
+		}
+		level = next
+	}
+	return depths, nil
+}
+
+// depthArgs reads the arguments of the depth functions: a Document or
+// NodeList, a second argument and an optional edge type filter.
+func depthArgs(name string, vals []ref.Val) (*sbom.NodeList, map[sbom.Edge_Type]struct{}, error) {
+	if len(vals) != 2 && len(vals) != 3 {
+		return nil, nil, fmt.Errorf("%s: incorrect number of params", name)
+	}
+	nl, err := nodeListFromVal(vals[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	var edgeTypes map[sbom.Edge_Type]struct{}
+	if len(vals) == 3 {
+		edgeTypes, err = edgeTypesFromArg(vals[2])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nl, edgeTypes, nil
+}
+
+// NodesAtDepth returns a NodeList with the nodes at a number of hops from
+// the root elements, following the shortest path. An optional relationship
+// type or list of types limits the edges that are traversed:
+//
+//	nl.get_nodes_at_depth(1)
+//	nl.get_nodes_at_depth(1, "DEPENDS_ON")
+var NodesAtDepth = func(vals ...ref.Val) ref.Val {
+	return nodesAtDepth(neverInterrupted, vals...)
+}
+
+// nodesAtDepth implements NodesAtDepth checking for interrupts
+func nodesAtDepth(interrupted InterruptCheck, vals ...ref.Val) ref.Val {
+	nl, edgeTypes, err := depthArgs("get_nodes_at_depth", vals)
+	if err != nil {
+		return types.NewErr("%w", err)
+	}
+	depth, ok := vals[1].Value().(int64)
+	if !ok {
+		return types.NewErr("depth must be an int, not %T", vals[1].Value())
+	}
+
+	depths, err := nodeDepths(nl, edgeTypes, interrupted)
+	if err != nil {
+		return types.NewErr("get_nodes_at_depth: %w", err)
+	}
+	return filterNodeList(nl, func(n *sbom.Node) bool {
+		d, ok := depths[n.GetId()]
+		return ok && d == depth
+	})
+}
+
+// GraphAtDepth returns the graph fragments that start at the nodes at a
+// number of hops from the root elements. The NodeList includes those nodes,
+// which become its root elements, everything they reach and the edges
+// traversed. An optional relationship type or list of types limits the
+// edges that are traversed:
+//
+//	nl.get_graph_at_depth(1)
+//	nl.get_graph_at_depth(1, ["DEPENDS_ON"])
+var GraphAtDepth = func(vals ...ref.Val) ref.Val {
+	return graphAtDepth(neverInterrupted, vals...)
+}
+
+// graphAtDepth implements GraphAtDepth checking for interrupts
+func graphAtDepth(interrupted InterruptCheck, vals ...ref.Val) ref.Val {
+	nl, edgeTypes, err := depthArgs("get_graph_at_depth", vals)
+	if err != nil {
+		return types.NewErr("%w", err)
+	}
+	depth, ok := vals[1].Value().(int64)
+	if !ok {
+		return types.NewErr("depth must be an int, not %T", vals[1].Value())
+	}
+
+	ret := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{},
+		},
+	}
+
+	depths, err := nodeDepths(nl, edgeTypes, interrupted)
+	if err != nil {
+		return types.NewErr("get_graph_at_depth: %w", err)
+	}
+	nodes := indexNodes(nl)
+	seen := map[string]struct{}{}
+	level := []string{}
+	for _, n := range nl.GetNodes() {
+		if d, ok := depths[n.GetId()]; ok && d == depth {
+			seen[n.GetId()] = struct{}{}
+			ret.AddNode(n.Copy())
+			ret.RootElements = append(ret.RootElements, n.GetId())
+			level = append(level, n.GetId())
+		}
+	}
+
+	graph := newGraphIndex(nl, edgeTypes)
+	for len(level) > 0 {
+		next := []string{}
+		for _, id := range level {
+			if interrupted() {
+				return types.NewErr("get_graph_at_depth: %w", ErrInterrupted)
+			}
+			for _, hop := range graph[id] {
+				if _, ok := nodes[hop.id]; !ok {
+					continue
+				}
+				ret.AddEdge(id, hop.edgeType, []string{hop.id})
+				if _, ok := seen[hop.id]; ok {
+					continue
+				}
+				seen[hop.id] = struct{}{}
+				ret.AddNode(nodes[hop.id].Copy())
+				next = append(next, hop.id)
+			}
+		}
+		level = next
+	}
+	return ret
+}
+
+// DepthOf returns the number of hops in the shortest path from the root
+// elements to a node, zero for the root elements themselves. Returns -1 if
+// the node is not found or not reachable. An optional relationship type or
+// list of types limits the edges that are traversed:
+//
+//	nl.depth_of("my-dependency")
+//	nl.depth_of("my-dependency", "DEPENDS_ON")
+var DepthOf = func(vals ...ref.Val) ref.Val {
+	return depthOf(neverInterrupted, vals...)
+}
+
+// depthOf implements DepthOf checking for interrupts
+func depthOf(interrupted InterruptCheck, vals ...ref.Val) ref.Val {
+	nl, edgeTypes, err := depthArgs("depth_of", vals)
+	if err != nil {
+		return types.NewErr("%w", err)
+	}
+	id, ok := vals[1].Value().(string)
+	if !ok {
+		return types.NewErr("node id must be a string, not %T", vals[1].Value())
+	}
+
+	depths, err := nodeDepths(nl, edgeTypes, interrupted)
+	if err != nil {
+		return types.NewErr("depth_of: %w", err)
+	}
+	if d, ok := depths[id]; ok {
+		return types.Int(d)
+	}
+	return types.Int(-1)
+}
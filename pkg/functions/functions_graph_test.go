@@ -0,0 +1,371 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+// testGraph returns a nodelist with the following graph:
+//
+//	app -DEPENDS_ON-> lib1 -DEPENDS_ON-> log4j
+//	app -DEPENDS_ON-> lib2 -DEPENDS_ON-> lib3 -DEPENDS_ON-> log4j
+//	lib3 -DEPENDS_ON-> lib2 (cycle)
+//	app -BUILD_TOOL_OF-> tool -DEPENDS_ON-> log4j
+//	app -CONTAINS-> file
+func testGraph() *elements.NodeList {
+	return &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				{Id: "app"}, {Id: "lib1"}, {Id: "lib2"}, {Id: "lib3"},
+				{Id: "log4j"}, {Id: "tool"}, {Id: "file"},
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "app", To: []string{"lib1", "lib2"}},
+				{Type: sbom.Edge_buildTool, From: "app", To: []string{"tool"}},
+				{Type: sbom.Edge_contains, From: "app", To: []string{"file"}},
+				{Type: sbom.Edge_dependsOn, From: "lib1", To: []string{"log4j"}},
+				{Type: sbom.Edge_dependsOn, From: "lib2", To: []string{"lib3"}},
+				{Type: sbom.Edge_dependsOn, From: "lib3", To: []string{"log4j", "lib2"}},
+				{Type: sbom.Edge_dependsOn, From: "tool", To: []string{"log4j"}},
+			},
+			RootElements: []string{"app"},
+		},
+	}
+}
+
+func fragmentIDs(t *testing.T, v ref.Val) []string {
+	t.Helper()
+	nl, ok := v.Value().(*sbom.NodeList)
+	require.True(t, ok)
+	ids := []string{}
+	for _, n := range nl.Nodes {
+		ids = append(ids, n.Id)
+	}
+	return ids
+}
+
+func TestNodePaths(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []ref.Val
+		mustErr  bool
+		expected [][]string
+	}{
+		{
+			name: "all-paths",
+			args: []ref.Val{types.String("app"), types.String("log4j"), types.Int(10)},
+			expected: [][]string{
+				{"app", "lib1", "log4j"},
+				{"app", "lib2", "lib3", "log4j"},
+				{"app", "tool", "log4j"},
+			},
+		},
+		{
+			name: "max-depth",
+			args: []ref.Val{types.String("app"), types.String("log4j"), types.Int(2)},
+			expected: [][]string{
+				{"app", "lib1", "log4j"},
+				{"app", "tool", "log4j"},
+			},
+		},
+		{
+			name: "edge-types",
+			args: []ref.Val{
+				types.String("app"), types.String("log4j"), types.Int(10),
+				types.NewStringList(types.DefaultTypeAdapter, []string{"DEPENDS_ON"}),
+			},
+			expected: [][]string{
+				{"app", "lib1", "log4j"},
+				{"app", "lib2", "lib3", "log4j"},
+			},
+		},
+		{
+			name: "edge-type",
+			args: []ref.Val{types.String("app"), types.String("log4j"), types.Int(10), types.String("DEPENDS_ON")},
+			expected: [][]string{
+				{"app", "lib1", "log4j"},
+				{"app", "lib2", "lib3", "log4j"},
+			},
+		},
+		{
+			name:     "no-path",
+			args:     []ref.Val{types.String("file"), types.String("log4j"), types.Int(10)},
+			expected: [][]string{},
+		},
+		{
+			name:     "unknown-node",
+			args:     []ref.Val{types.String("app"), types.String("nope"), types.Int(10)},
+			expected: [][]string{},
+		},
+		{
+			name:    "invalid-depth",
+			args:    []ref.Val{types.String("app"), types.String("log4j"), types.Int(0)},
+			mustErr: true,
+		},
+		{
+			name: "invalid-edge-type",
+			args: []ref.Val{
+				types.String("app"), types.String("log4j"), types.Int(10),
+				types.NewStringList(types.DefaultTypeAdapter, []string{"LIKES"}),
+			},
+			mustErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NodePaths(append([]ref.Val{testGraph()}, tc.args...)...)
+			if tc.mustErr {
+				require.True(t, types.IsError(res))
+				return
+			}
+			require.False(t, types.IsError(res), res)
+			list, ok := res.(traits.Lister)
+			require.True(t, ok)
+
+			paths := [][]string{}
+			for it := list.Iterator(); it.HasNext() == types.True; {
+				paths = append(paths, fragmentIDs(t, it.Next()))
+			}
+			require.Equal(t, tc.expected, paths)
+		})
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []ref.Val
+		mustErr  bool
+		expected []string
+		edges    int
+	}{
+		{"shortest", []ref.Val{types.String("app"), types.String("log4j")}, false, []string{"app", "lib1", "log4j"}, 2},
+		{"same-node", []ref.Val{types.String("app"), types.String("app")}, false, []string{"app"}, 0},
+		{"no-path", []ref.Val{types.String("log4j"), types.String("app")}, false, []string{}, 0},
+		{
+			"edge-types",
+			[]ref.Val{
+				types.String("app"), types.String("log4j"),
+				types.NewStringList(types.DefaultTypeAdapter, []string{"BUILD_TOOL_OF", "DEPENDS_ON"}),
+			},
+			false, []string{"app", "lib1", "log4j"}, 2,
+		},
+		{
+			"edge-type",
+			[]ref.Val{types.String("app"), types.String("log4j"), types.String("DEPENDS_ON")},
+			false, []string{"app", "lib1", "log4j"}, 2,
+		},
+		{
+			"edge-types-build",
+			[]ref.Val{
+				types.String("app"), types.String("tool"),
+				types.NewStringList(types.DefaultTypeAdapter, []string{"DEPENDS_ON"}),
+			},
+			false, []string{}, 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := ShortestPath(append([]ref.Val{testGraph()}, tc.args...)...)
+			if tc.mustErr {
+				require.True(t, types.IsError(res))
+				return
+			}
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, tc.expected, fragmentIDs(t, res))
+			nl, ok := res.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Len(t, nl.Edges, tc.edges)
+		})
+	}
+
+	// Documents are supported too
+	doc := &elements.Document{Document: &sbom.Document{NodeList: testGraph().NodeList}}
+	res := ShortestPath(doc, types.String("lib2"), types.String("log4j"))
+	require.Equal(t, []string{"lib2", "lib3", "log4j"}, fragmentIDs(t, res))
+}
+
+func TestNodeAncestors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []ref.Val
+		expected []string
+		roots    []string
+		edges    int
+	}{
+		{
+			"all", []ref.Val{types.String("log4j"), types.Int(10)},
+			[]string{"log4j", "lib1", "lib3", "tool", "app", "lib2"}, []string{"app"}, 6,
+		},
+		{
+			"depth-1", []ref.Val{types.String("log4j"), types.Int(1)},
+			[]string{"log4j", "lib1", "lib3", "tool"}, []string{"lib1", "lib3", "tool"}, 3,
+		},
+		{
+			"edge-types", []ref.Val{types.String("tool"), types.Int(10), types.NewStringList(types.DefaultTypeAdapter, []string{"DEPENDS_ON"})},
+			[]string{"tool"}, []string{"tool"}, 0,
+		},
+		{
+			"build-tool", []ref.Val{types.String("tool"), types.Int(10), types.NewStringList(types.DefaultTypeAdapter, []string{"BUILD_TOOL_OF"})},
+			[]string{"tool", "app"}, []string{"app"}, 1,
+		},
+		{
+			"edge-type", []ref.Val{types.String("tool"), types.Int(10), types.String("BUILD_TOOL_OF")},
+			[]string{"tool", "app"}, []string{"app"}, 1,
+		},
+		{
+			"root", []ref.Val{types.String("app"), types.Int(10)},
+			[]string{"app"}, []string{"app"}, 0,
+		},
+		{
+			"not-found", []ref.Val{types.String("nope"), types.Int(10)},
+			[]string{}, []string{}, 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NodeAncestors(append([]ref.Val{testGraph()}, tc.args...)...)
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, tc.expected, fragmentIDs(t, res))
+			nl, ok := res.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Equal(t, tc.roots, nl.RootElements)
+			require.Len(t, nl.Edges, tc.edges)
+		})
+	}
+
+	res := NodeAncestors(testGraph(), types.String("log4j"), types.Int(10), types.NewStringList(types.DefaultTypeAdapter, []string{"LIKES"}))
+	require.True(t, types.IsError(res))
+}
+
+func TestEdgesByType(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		arg      ref.Val
+		expected int
+		mustErr  bool
+	}{
+		{"spdx", types.String("DEPENDS_ON"), 5, false},
+		{"protobom", types.String("buildTool"), 1, false},
+		{"list", types.NewStringList(types.DefaultTypeAdapter, []string{"CONTAINS", "BUILD_TOOL_OF"}), 2, false},
+		{"none", types.String("DEV_DEPENDENCY_OF"), 0, false},
+		{"unknown", types.String("LIKES"), 0, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := EdgesByType(testGraph(), tc.arg)
+			if tc.mustErr {
+				require.True(t, types.IsError(res))
+				return
+			}
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, types.Int(tc.expected), res.(traits.Sizer).Size())
+		})
+	}
+}
+
+func TestRelatedNodes(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		id       string
+		arg      ref.Val
+		expected []string
+		edges    int
+	}{
+		{"outgoing", "app", types.String("DEPENDS_ON"), []string{"lib1", "lib2"}, 0},
+		{"both-directions", "lib2", types.String("DEPENDS_ON"), []string{"app", "lib3"}, 0},
+		{"incoming", "tool", types.String("BUILD_TOOL_OF"), []string{"app"}, 0},
+		{"list", "app", types.NewStringList(types.DefaultTypeAdapter, []string{"CONTAINS", "BUILD_TOOL_OF"}), []string{"tool", "file"}, 0},
+		{"not-found", "nope", types.String("DEPENDS_ON"), []string{}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := RelatedNodes(testGraph(), types.String(tc.id), tc.arg)
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, tc.expected, fragmentIDs(t, res))
+			nl, ok := res.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Len(t, nl.Edges, tc.edges)
+		})
+	}
+
+	require.True(t, types.IsError(RelatedNodes(testGraph(), types.String("app"), types.String("LIKES"))))
+	require.True(t, types.IsError(RelatedNodes(testGraph(), types.String("app"))))
+}
+
+func TestNodesAtDepth(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []ref.Val
+		expected []string
+	}{
+		{"roots", []ref.Val{types.Int(0)}, []string{"app"}},
+		{"direct", []ref.Val{types.Int(1)}, []string{"lib1", "lib2", "tool", "file"}},
+		{"transitive", []ref.Val{types.Int(2)}, []string{"lib3", "log4j"}},
+		{"too-deep", []ref.Val{types.Int(3)}, []string{}},
+		{"edge-type", []ref.Val{types.Int(1), types.String("DEPENDS_ON")}, []string{"lib1", "lib2"}},
+		{"edge-types", []ref.Val{types.Int(1), types.NewStringList(types.DefaultTypeAdapter, []string{"CONTAINS", "BUILD_TOOL_OF"})}, []string{"tool", "file"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NodesAtDepth(append([]ref.Val{testGraph()}, tc.args...)...)
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, tc.expected, fragmentIDs(t, res))
+		})
+	}
+
+	require.True(t, types.IsError(NodesAtDepth(testGraph(), types.String("1"))))
+	require.True(t, types.IsError(NodesAtDepth(testGraph(), types.Int(1), types.String("LIKES"))))
+}
+
+func TestGraphAtDepth(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []ref.Val
+		expected []string
+		roots    []string
+		edges    int
+	}{
+		{"roots", []ref.Val{types.Int(0)}, []string{"app", "lib1", "lib2", "tool", "file", "log4j", "lib3"}, []string{"app"}, 7},
+		{"direct", []ref.Val{types.Int(1)}, []string{"lib1", "lib2", "tool", "file", "log4j", "lib3"}, []string{"lib1", "lib2", "tool", "file"}, 4},
+		{"edge-type", []ref.Val{types.Int(2), types.String("DEPENDS_ON")}, []string{"lib3", "log4j", "lib2"}, []string{"lib3", "log4j"}, 2},
+		{"too-deep", []ref.Val{types.Int(5)}, []string{}, []string{}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := GraphAtDepth(append([]ref.Val{testGraph()}, tc.args...)...)
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, tc.expected, fragmentIDs(t, res))
+			nl, ok := res.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Equal(t, tc.roots, nl.RootElements)
+			require.Len(t, nl.Edges, tc.edges)
+		})
+	}
+}
+
+func TestDepthOf(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []ref.Val
+		expected int64
+	}{
+		{"root", []ref.Val{types.String("app")}, 0},
+		{"direct", []ref.Val{types.String("tool")}, 1},
+		{"shortest", []ref.Val{types.String("log4j")}, 2},
+		{"cycle", []ref.Val{types.String("lib3")}, 2},
+		{"not-found", []ref.Val{types.String("nope")}, -1},
+		{"edge-type", []ref.Val{types.String("log4j"), types.String("DEPENDS_ON")}, 2},
+		{"unreachable", []ref.Val{types.String("tool"), types.String("DEPENDS_ON")}, -1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := DepthOf(append([]ref.Val{testGraph()}, tc.args...)...)
+			require.Equal(t, types.Int(tc.expected), res)
+		})
+	}
+
+	require.True(t, types.IsError(DepthOf(testGraph())))
+}
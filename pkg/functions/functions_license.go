@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/license"
+)
+
+// isNoLicense returns true if the string does not carry license information
+func isNoLicense(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.EqualFold(s, "NONE") || strings.EqualFold(s, "NOASSERTION")
+}
+
+// nodeLicenseExpression returns the license expression that applies to a
+// node: the concluded license if set or all the declared licenses joined
+// with AND. Returns nil if the node has no license data.
+func nodeLicenseExpression(n *sbom.Node) (*license.Expression, error) {
+	if !isNoLicense(n.GetLicenseConcluded()) {
+		return license.Parse(n.GetLicenseConcluded())
+	}
+
+	var ret *license.Expression
+	for _, l := range n.GetLicenses() {
+		if isNoLicense(l) {
+			continue
+		}
+		e, err := license.Parse(l)
+		if err != nil {
+			return nil, err
+		}
+		if ret == nil {
+			ret = e
+			continue
+		}
+		ret = &license.Expression{Operator: license.OperatorAnd, Left: ret, Right: e}
+	}
+	return ret, nil
+}
+
+// nodeLicenseIDs returns all the license identifiers found in the concluded
+// and declared licenses of a node. Licenses that are not valid SPDX
+// expressions (eg free text like "Apache License, Version 2.0") are
+// returned as they are.
+func nodeLicenseIDs(n *sbom.Node) []string {
+	ret := []string{}
+	for _, l := range append([]string{n.GetLicenseConcluded()}, n.GetLicenses()...) {
+		if isNoLicense(l) {
+			continue
+		}
+		ids := []string{strings.TrimSpace(l)}
+		if e, err := license.Parse(l); err == nil {
+			ids = e.LicenseIDs()
+		}
+		for _, id := range ids {
+			if !slices.Contains(ret, id) {
+				ret = append(ret, id)
+			}
+		}
+	}
+	return ret
+}
+
+// NodeLicenseIDs returns the license identifiers in the node's concluded
+// and declared license expressions. Licenses that cannot be parsed are
+// returned verbatim:
+//
+//	node.license_ids()
+var NodeLicenseIDs = func(lhs ref.Val) ref.Val {
+	n, ok := lhs.Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("license_ids() only applies to Node")
+	}
+	return types.NewStringList(types.DefaultTypeAdapter, nodeLicenseIDs(n))
+}
+
+// NodeLicenseSatisfies checks if the node's license expression can be
+// fulfilled using only the licenses in a list. Nodes without license data
+// never satisfy the list.
+//
+//	node.license_satisfies(["MIT", "Apache-2.0"])
+var NodeLicenseSatisfies = func(lhs, rhs ref.Val) ref.Val {
+	n, ok := lhs.Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("license_satisfies() only applies to Node")
+	}
+	allowed, err := stringsFromVal(rhs)
+	if err != nil {
+		return types.NewErr("license_satisfies: %w", err)
+	}
+
+	e, err := nodeLicenseExpression(n)
+	if err != nil {
+		return types.NewErr("license_satisfies: node %q: %w", n.GetId(), err)
+	}
+	if e == nil {
+		return types.False
+	}
+
+	ok, err = e.Satisfies(allowed)
+	if err != nil {
+		return types.NewErr("license_satisfies: %w", err)
+	}
+	return types.Bool(ok)
+}
+
+// NodeIsCopyleft returns true if the license expression of the node cannot
+// be fulfilled without a copyleft license.
+//
+//	node.is_copyleft()
+var NodeIsCopyleft = func(lhs ref.Val) ref.Val {
+	n, ok := lhs.Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("is_copyleft() only applies to Node")
+	}
+	e, err := nodeLicenseExpression(n)
+	if err != nil {
+		return types.NewErr("is_copyleft: node %q: %w", n.GetId(), err)
+	}
+	if e == nil {
+		return types.False
+	}
+	return types.Bool(e.IsCopyleft())
+}
+
+// NodesByLicense returns a NodeList with the nodes that have a license
+// identifier matching a glob pattern. Licenses that cannot be parsed are
+// matched as they are, like in NodeLicenseIDs.
+//
+//	sbom.get_nodes_by_license("GPL-*")
+var NodesByLicense = func(lhs, rhs ref.Val) ref.Val {
+	pattern, ok := rhs.Value().(string)
+	if !ok {
+		return types.NewErr("argument to get_nodes_by_license must be a string")
+	}
+	if _, err := license.Match(pattern, ""); err != nil {
+		return types.NewErr("get_nodes_by_license: invalid pattern %q: %w", pattern, err)
+	}
+
+	nl, err := nodeListFromVal(lhs)
+	if err != nil {
+		return types.NewErr("get_nodes_by_license: %w", err)
+	}
+
+	return filterNodeList(nl, func(n *sbom.Node) bool {
+		return slices.ContainsFunc(nodeLicenseIDs(n), func(id string) bool {
+			ok, _ := license.Match(pattern, id)
+			return ok
+		})
+	})
+}
+
+// LicenseInfo returns the data of a license in the bundled SPDX license
+// list:
+//
+//	protobom.license_info("MIT").osi_approved
+var LicenseInfo = func(_, rhs ref.Val) ref.Val {
+	id, ok := rhs.Value().(string)
+	if !ok {
+		return types.NewErr("license identifier must be a string")
+	}
+	lic, ok := license.DefaultList().License(id)
+	if !ok {
+		return types.NewErr("license %q not found in SPDX license list %s", id, license.DefaultList().Version)
+	}
+	return types.DefaultTypeAdapter.NativeToValue(map[string]any{
+		"id":           lic.ID,
+		"deprecated":   lic.Deprecated,
+		"osi_approved": lic.OsiApproved,
+		"fsf_libre":    lic.FsfLibre,
+		"copyleft":     license.IsCopyleft(lic.ID),
+	})
+}
+
+// IsValidLicense checks if a string is a license expression made of
+// identifiers in the SPDX license list or license references.
+//
+//	protobom.is_valid_license("MIT OR Apache-2.0")
+var IsValidLicense = func(_, rhs ref.Val) ref.Val {
+	s, ok := rhs.Value().(string)
+	if !ok {
+		return types.NewErr("license expression must be a string")
+	}
+	e, err := license.Parse(s)
+	if err != nil {
+		return types.False
+	}
+	return types.Bool(e.Validate() == nil)
+}
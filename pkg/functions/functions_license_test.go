@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+func licensedNode(id, concluded string, declared ...string) *sbom.Node {
+	return &sbom.Node{Id: id, LicenseConcluded: concluded, Licenses: declared}
+}
+
+func TestNodeLicenseFunctions(t *testing.T) {
+	allowed := types.NewStringList(types.DefaultTypeAdapter, []string{"MIT", "Apache-2.0"})
+	for _, tc := range []struct {
+		name      string
+		node      *sbom.Node
+		ids       []string
+		satisfies bool
+		copyleft  bool
+		mustErr   bool
+	}{
+		{"concluded", licensedNode("a", "MIT OR GPL-2.0-only", "GPL-2.0-only"), []string{"MIT", "GPL-2.0-only"}, true, false, false},
+		{"declared", licensedNode("b", "", "MIT", "GPL-3.0-or-later"), []string{"MIT", "GPL-3.0-or-later"}, false, true, false},
+		{"noassertion", licensedNode("c", "NOASSERTION", "Apache-2.0"), []string{"Apache-2.0"}, true, false, false},
+		{"none", licensedNode("d", "NONE"), []string{}, false, false, false},
+		{"invalid", licensedNode("e", "MIT AND"), []string{"MIT AND"}, false, false, true},
+		{"free-text", licensedNode("f", "", "Apache License, Version 2.0", "MIT"), []string{"Apache License, Version 2.0", "MIT"}, false, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &elements.Node{Node: tc.node}
+			ids := NodeLicenseIDs(n)
+			satisfies := NodeLicenseSatisfies(n, allowed)
+			copyleft := NodeIsCopyleft(n)
+			// Unparsable licenses are returned verbatim
+			require.Equal(t, types.True, types.NewStringList(types.DefaultTypeAdapter, tc.ids).Equal(ids), ids)
+			if tc.mustErr {
+				require.True(t, types.IsError(satisfies))
+				require.True(t, types.IsError(copyleft))
+				return
+			}
+			require.Equal(t, types.Bool(tc.satisfies), satisfies)
+			require.Equal(t, types.Bool(tc.copyleft), copyleft)
+		})
+	}
+}
+
+func TestNodesByLicense(t *testing.T) {
+	nl := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				licensedNode("app", "Apache-2.0"),
+				licensedNode("gpl", "GPL-2.0-only WITH Classpath-exception-2.0"),
+				licensedNode("lgpl", "", "LGPL-2.1-or-later"),
+				licensedNode("broken", "MIT AND"),
+				licensedNode("free-text", "", "GNU General Public License v2.0"),
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "app", To: []string{"gpl", "lgpl", "broken", "free-text"}},
+			},
+			RootElements: []string{"app"},
+		},
+	}
+
+	for _, tc := range []struct {
+		pattern  string
+		expected []string
+		mustErr  bool
+	}{
+		{"GPL-*", []string{"gpl"}, false},
+		{"*GPL-*", []string{"gpl", "lgpl"}, false},
+		{"apache-2.0", []string{"app"}, false},
+		{"MIT", []string{}, false},
+		{"MIT AND", []string{"broken"}, false},
+		{"GNU General Public*", []string{"free-text"}, false},
+		{"[", nil, true},
+	} {
+		t.Run(tc.pattern, func(t *testing.T) {
+			res := NodesByLicense(nl, types.String(tc.pattern))
+			if tc.mustErr {
+				require.True(t, types.IsError(res))
+				return
+			}
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, tc.expected, fragmentIDs(t, res))
+		})
+	}
+}
+
+func TestLicenseInfo(t *testing.T) {
+	res := LicenseInfo(&elements.Protobom{}, types.String("gpl-3.0-only"))
+	require.False(t, types.IsError(res), res)
+	m, ok := res.(traits.Mapper)
+	require.True(t, ok)
+	require.Equal(t, types.String("GPL-3.0-only"), m.Get(types.String("id")))
+	require.Equal(t, types.True, m.Get(types.String("osi_approved")))
+	require.Equal(t, types.True, m.Get(types.String("fsf_libre")))
+	require.Equal(t, types.True, m.Get(types.String("copyleft")))
+	require.Equal(t, types.False, m.Get(types.String("deprecated")))
+
+	require.True(t, types.IsError(LicenseInfo(&elements.Protobom{}, types.String("Foo-1.0"))))
+
+	require.Equal(t, types.True, IsValidLicense(&elements.Protobom{}, types.String("MIT OR LicenseRef-x")))
+	require.Equal(t, types.False, IsValidLicense(&elements.Protobom{}, types.String("MIT OR Foo-1.0")))
+	require.Equal(t, types.False, IsValidLicense(&elements.Protobom{}, types.String("MIT OR")))
+}
@@ -6,13 +6,24 @@ package functions
 import (
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
 	"github.com/protobom/protobom/pkg/sbom"
 
 	"github.com/protobom/cel/pkg/adapter"
 	"github.com/protobom/cel/pkg/elements"
 )
 
+// NodeDescendants returns a node list with the descendants of a node up to
+// maxDepth levels deep.
 func NodeDescendants(vals ...ref.Val) ref.Val {
+	return nodeDescendants(neverInterrupted, vals...)
+}
+
+// nodeDescendants implements NodeDescendants checking for interrupts. It
+// walks the graph like the protobom NodeList.NodeDescendants method: up to
+// maxDepth levels from the node, without expanding other root elements, and
+// connecting all the descendants to the node with an ancestor edge.
+func nodeDescendants(interrupted InterruptCheck, vals ...ref.Val) ref.Val {
 	if len(vals) != 3 {
 		return types.NewErr("incorrect number of params")
 	}
@@ -28,7 +39,56 @@ func NodeDescendants(vals ...ref.Val) ref.Val {
 	if !ok {
 		return types.NewErr("maxDepth must be an int, not %T", vals[2].Value())
 	}
-	return &elements.NodeList{NodeList: nl.NodeDescendants(id, int(maxDepth))}
+
+	nodes := indexNodes(nl)
+	start, ok := nodes[id]
+	if !ok {
+		return &elements.NodeList{NodeList: &sbom.NodeList{}}
+	}
+
+	roots := map[string]struct{}{}
+	for _, r := range nl.GetRootElements() {
+		roots[r] = struct{}{}
+	}
+	graph := newGraphIndex(nl, nil)
+
+	ret := &sbom.NodeList{
+		Nodes:        []*sbom.Node{start},
+		Edges:        []*sbom.Edge{},
+		RootElements: []string{id},
+	}
+	ancestorEdge := &sbom.Edge{Type: sbom.Edge_ancestor, From: id, To: []string{}}
+	seen := map[string]struct{}{id: {}}
+	level := []string{id}
+	for depth := int64(0); depth < maxDepth && len(level) > 0; depth++ {
+		next := []string{}
+		for _, nid := range level {
+			if interrupted() {
+				return types.NewErr("get_node_descendants: %w", ErrInterrupted)
+			}
+			if _, ok := roots[nid]; ok && nid != id {
+				continue
+			}
+			for _, hop := range graph[nid] {
+				if _, ok := seen[hop.id]; ok {
+					continue
+				}
+				n, ok := nodes[hop.id]
+				if !ok {
+					continue
+				}
+				seen[hop.id] = struct{}{}
+				ret.Nodes = append(ret.Nodes, n)
+				ancestorEdge.To = append(ancestorEdge.To, hop.id)
+				next = append(next, hop.id)
+			}
+		}
+		level = next
+	}
+	if len(ancestorEdge.To) > 0 {
+		ret.Edges = append(ret.Edges, ancestorEdge)
+	}
+	return &elements.NodeList{NodeList: ret}
 }
 
 // GetNodesByName takes a name and returns a list of nodes matching it
@@ -71,3 +131,34 @@ var NodesByPurlType = func(lhs, rhs ref.Val) ref.Val {
 		NodeList: nl,
 	}
 }
+
+// SelectNodes returns a NodeList with the nodes of a NodeList that are also
+// in a list of nodes, matched by their ID. Only the edges among the selected
+// nodes are kept and the root elements are recomputed. This is the function
+// that the where() macro expands to:
+//
+//	nl.where(n, n.name.startsWith("k8s.io"))
+var SelectNodes = func(lhs, rhs ref.Val) ref.Val {
+	nl, err := nodeListFromVal(lhs)
+	if err != nil {
+		return types.NewErr("where: %w", err)
+	}
+	list, ok := rhs.(traits.Lister)
+	if !ok {
+		return types.NewErr("where: expected a list of nodes, not %T", rhs.Value())
+	}
+
+	selected := map[string]struct{}{}
+	for it := list.Iterator(); it.HasNext() == types.True; {
+		n, ok := it.Next().Value().(*sbom.Node)
+		if !ok {
+			return types.NewErr("where: expected a list of nodes")
+		}
+		selected[n.GetId()] = struct{}{}
+	}
+
+	return filterNodeList(nl, func(n *sbom.Node) bool {
+		_, ok := selected[n.GetId()]
+		return ok
+	})
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/protobom/cel/pkg/adapter"
+	"github.com/protobom/cel/pkg/elements"
+	"github.com/protobom/cel/pkg/osv"
+)
+
+// VulnerabilityPropertyName is the name of the node properties that record
+// the vulnerabilities matched to a node.
+const VulnerabilityPropertyName = "vulnerability"
+
+// VulnerabilityMatch is a vulnerability found in a node
+type VulnerabilityMatch struct {
+	NodeID   string
+	VulnID   string
+	Aliases  []string
+	Summary  string
+	Severity string
+	FixedIn  []string
+}
+
+// MatchVulnerabilities returns the vulnerabilities in the OSV database that
+// affect the nodes of a NodeList. Nodes are matched by their package URL and
+// their version is checked against the affected versions and ranges using
+// the versioning scheme of their purl type.
+func MatchVulnerabilities(nl *sbom.NodeList, db *osv.Database) []VulnerabilityMatch {
+	ret := []VulnerabilityMatch{}
+	for _, n := range nl.GetNodes() {
+		version := nodeVersion(n)
+		if n.Purl() == "" || version == "" {
+			continue
+		}
+
+		compare, err := versionComparator(nodeVersionScheme(n))
+		if err != nil {
+			continue
+		}
+
+		seen := map[string]struct{}{}
+		for _, entry := range db.Lookup(string(n.Purl())) {
+			affected, fixedIn := entry.Affected.Affects(version, osv.CompareFunc(compare))
+			if !affected {
+				continue
+			}
+
+			v := entry.Vulnerability
+			if _, ok := seen[v.ID]; ok {
+				continue
+			}
+			seen[v.ID] = struct{}{}
+
+			aliases := v.Aliases
+			if aliases == nil {
+				aliases = []string{}
+			}
+			ret = append(ret, VulnerabilityMatch{
+				NodeID:   n.GetId(),
+				VulnID:   v.ID,
+				Aliases:  aliases,
+				Summary:  v.Summary,
+				Severity: v.SeverityLabel(),
+				FixedIn:  fixedIn,
+			})
+		}
+	}
+	return ret
+}
+
+// vulnerabilityMatchesToValue returns a list of matches as a CEL list of maps
+func vulnerabilityMatchesToValue(matches []VulnerabilityMatch) ref.Val {
+	l := make([]ref.Val, 0, len(matches))
+	for _, m := range matches {
+		l = append(l, types.NewStringInterfaceMap(adapter.ProtobomTypeAdapter{}, map[string]any{
+			"node_id":  m.NodeID,
+			"vuln_id":  m.VulnID,
+			"aliases":  m.Aliases,
+			"summary":  m.Summary,
+			"severity": m.Severity,
+			"fixed_in": m.FixedIn,
+		}))
+	}
+	return types.NewRefValList(adapter.ProtobomTypeAdapter{}, l)
+}
+
+// LoadOSV reads the OSV advisories in a directory, zip file or JSON file
+// into a database that can be passed to match_vulnerabilities:
+//
+//	protobom.load_osv("/data/osv/npm.zip")
+var LoadOSV = func(_, pathVal ref.Val) ref.Val {
+	path, ok := pathVal.Value().(string)
+	if !ok {
+		return types.NewErr("load_osv: path must be a string")
+	}
+
+	db, err := osv.Load(path)
+	if err != nil {
+		return types.NewErr("load_osv: %w", err)
+	}
+	return &elements.OSVDatabase{Database: db}
+}
+
+// MatchVulnerabilitiesBinding matches the nodes of a Document or NodeList
+// against the advisories in an OSV database:
+//
+//	protobom.match_vulnerabilities(sboms[0], protobom.load_osv("/data/osv/npm.zip"))
+var MatchVulnerabilitiesBinding = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 3 {
+		return types.NewErr("match_vulnerabilities takes two arguments")
+	}
+	nl, err := nodeListFromVal(vals[1])
+	if err != nil {
+		return types.NewErr("match_vulnerabilities: %w", err)
+	}
+	db, ok := vals[2].Value().(*osv.Database)
+	if !ok {
+		return types.NewErr("match_vulnerabilities: expected an OSV database, not %T", vals[2].Value())
+	}
+	return vulnerabilityMatchesToValue(MatchVulnerabilities(nl, db))
+}
+
+// WithVulnerabilities returns a copy of a Document or NodeList where the
+// matched nodes record their vulnerabilities as properties, one for each
+// vulnerability ID. It takes the list returned by match_vulnerabilities:
+//
+//	sboms[0].with_vulnerabilities(protobom.match_vulnerabilities(sboms[0], db))
+var WithVulnerabilities = func(lhs, rhs ref.Val) ref.Val {
+	nl, err := nodeListFromVal(lhs)
+	if err != nil {
+		return types.NewErr("with_vulnerabilities: %w", err)
+	}
+	vulns, err := vulnerabilityIDsFromVal(rhs)
+	if err != nil {
+		return types.NewErr("with_vulnerabilities: %w", err)
+	}
+
+	ret := shallowNodeListCopy(nl)
+	for i, n := range ret.Nodes {
+		ids, ok := vulns[n.GetId()]
+		if !ok {
+			continue
+		}
+		clone, ok := proto.Clone(n).(*sbom.Node)
+		if !ok {
+			return types.NewErr("with_vulnerabilities: unable to copy node")
+		}
+		for _, id := range ids {
+			if slices.ContainsFunc(clone.Properties, func(p *sbom.Property) bool {
+				return p.GetName() == VulnerabilityPropertyName && p.GetData() == id
+			}) {
+				continue
+			}
+			clone.Properties = append(clone.Properties, &sbom.Property{Name: VulnerabilityPropertyName, Data: id})
+		}
+		ret.Nodes[i] = clone
+	}
+
+	if doc, ok := lhs.Value().(*sbom.Document); ok {
+		return documentWithNodeList(doc, ret.NodeList)
+	}
+	return ret
+}
+
+// vulnerabilityIDsFromVal reads a list of vulnerability matches and returns
+// the vulnerability IDs keyed by node ID.
+func vulnerabilityIDsFromVal(val ref.Val) (map[string][]string, error) {
+	list, ok := val.(traits.Lister)
+	if !ok {
+		return nil, errors.New("expected a list of vulnerability matches")
+	}
+
+	ret := map[string][]string{}
+	for it := list.Iterator(); it.HasNext() == types.True; {
+		m, ok := it.Next().(traits.Mapper)
+		if !ok {
+			return nil, errors.New("expected a list of vulnerability matches")
+		}
+		nodeID, ok := m.Get(types.String("node_id")).Value().(string)
+		if !ok {
+			return nil, errors.New("vulnerability match has no node_id")
+		}
+		vulnID, ok := m.Get(types.String("vuln_id")).Value().(string)
+		if !ok {
+			return nil, errors.New("vulnerability match has no vuln_id")
+		}
+		ret[nodeID] = append(ret[nodeID], vulnID)
+	}
+	return ret, nil
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+	"github.com/protobom/cel/pkg/osv"
+)
+
+func osvTestNode(id, purl, version string) *sbom.Node {
+	return &sbom.Node{
+		Id:      id,
+		Name:    id,
+		Version: version,
+		Identifiers: map[int32]string{
+			int32(sbom.SoftwareIdentifierType_PURL): purl,
+		},
+	}
+}
+
+func osvTestNodeList() *sbom.NodeList {
+	return &sbom.NodeList{
+		Nodes: []*sbom.Node{
+			osvTestNode("braces", "pkg:npm/braces@3.0.2", "3.0.2"),
+			osvTestNode("braces-fixed", "pkg:npm/braces@3.0.3", "3.0.3"),
+			osvTestNode("crypto", "pkg:golang/golang.org/x/crypto@v0.11.0", "v0.11.0"),
+			osvTestNode("micromatch", "pkg:npm/micromatch@4.0.2", "4.0.2"),
+			{Id: "no-purl", Name: "no-purl", Version: "1.0.0"},
+		},
+	}
+}
+
+func TestMatchVulnerabilities(t *testing.T) {
+	db, err := osv.Load("../osv/testdata/osv")
+	require.NoError(t, err)
+
+	matches := MatchVulnerabilities(osvTestNodeList(), db)
+	require.Equal(t, []VulnerabilityMatch{
+		{
+			NodeID:   "braces",
+			VulnID:   "GHSA-grv7-fg5c-xmjg",
+			Aliases:  []string{"CVE-2024-4068"},
+			Summary:  "Uncontrolled resource consumption in braces",
+			Severity: "HIGH",
+			FixedIn:  []string{"3.0.3"},
+		},
+		{
+			NodeID:   "crypto",
+			VulnID:   "GO-2023-2402",
+			Aliases:  []string{"CVE-2023-48795", "GHSA-45x7-px36-x8w8"},
+			Summary:  "Man-in-the-middle attacker can compromise integrity of secure channel in golang.org/x/crypto",
+			Severity: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:N",
+			FixedIn:  []string{"0.17.0"},
+		},
+	}, matches)
+}
+
+func TestWithVulnerabilities(t *testing.T) {
+	db, err := osv.Load("../osv/testdata/osv")
+	require.NoError(t, err)
+
+	nl := osvTestNodeList()
+	matches := vulnerabilityMatchesToValue(MatchVulnerabilities(nl, db))
+
+	for _, tc := range []struct {
+		name string
+		val  func() any
+	}{
+		{"nodelist", func() any { return WithVulnerabilities(&elements.NodeList{NodeList: nl}, matches).Value() }},
+		{"document", func() any {
+			doc := &elements.Document{Document: &sbom.Document{Metadata: &sbom.Metadata{}, NodeList: nl}}
+			ret, ok := WithVulnerabilities(doc, matches).Value().(*sbom.Document)
+			require.True(t, ok)
+			return ret.GetNodeList()
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, ok := tc.val().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Equal(t, []*sbom.Property{{Name: VulnerabilityPropertyName, Data: "GHSA-grv7-fg5c-xmjg"}}, ret.GetNodes()[0].GetProperties())
+			require.Empty(t, ret.GetNodes()[1].GetProperties())
+			require.Equal(t, []*sbom.Property{{Name: VulnerabilityPropertyName, Data: "GO-2023-2402"}}, ret.GetNodes()[2].GetProperties())
+
+			// The original nodes are not modified
+			require.Empty(t, nl.GetNodes()[0].GetProperties())
+			require.Empty(t, nl.GetNodes()[2].GetProperties())
+		})
+	}
+
+	// Matching twice does not duplicate the properties
+	twice, ok := WithVulnerabilities(WithVulnerabilities(&elements.NodeList{NodeList: nl}, matches), matches).Value().(*sbom.NodeList)
+	require.True(t, ok)
+	require.Len(t, twice.GetNodes()[0].GetProperties(), 1)
+
+	require.True(t, types.IsError(WithVulnerabilities(&elements.NodeList{NodeList: nl}, types.String("x"))))
+}
+
+func TestMatchVulnerabilitiesBinding(t *testing.T) {
+	db := LoadOSV(&elements.Protobom{}, types.String("../osv/testdata/osv"))
+	require.False(t, types.IsError(db), db)
+	loaded, ok := db.Value().(*osv.Database)
+	require.True(t, ok)
+	require.Len(t, loaded.Vulnerabilities, 4)
+
+	nl := &elements.NodeList{NodeList: osvTestNodeList()}
+	res := MatchVulnerabilitiesBinding(&elements.Protobom{}, nl, db)
+	require.False(t, types.IsError(res), res)
+	matches, ok := res.(traits.Sizer)
+	require.True(t, ok)
+	require.Equal(t, types.Int(len(MatchVulnerabilities(nl.NodeList, loaded))), matches.Size())
+
+	require.True(t, types.IsError(MatchVulnerabilitiesBinding(&elements.Protobom{}, nl, types.String("../osv/testdata/osv"))))
+	require.True(t, types.IsError(LoadOSV(&elements.Protobom{}, types.String("../osv/testdata/nope"))))
+}
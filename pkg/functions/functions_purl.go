@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/package-url/packageurl-go"
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+// globPlaceholder stands for the asterisks of a purl pattern while it is
+// canonicalized
+const globPlaceholder = "purlpatternglob"
+
+// purlPattern is a package URL used to look up nodes. Components missing
+// from the pattern (version, qualifiers and subpath) are ignored when
+// matching and an asterisk matches any sequence of characters.
+type purlPattern struct {
+	purl          string
+	glob          *regexp.Regexp
+	hasVersion    bool
+	hasQualifiers bool
+	hasSubpath    bool
+}
+
+// newPurlPattern parses a purl pattern string
+func newPurlPattern(pattern string) (*purlPattern, error) {
+	if !strings.HasPrefix(pattern, "pkg:") {
+		return nil, fmt.Errorf("invalid purl pattern %q: must start with pkg:", pattern)
+	}
+
+	p := &purlPattern{purl: pattern}
+	rest, subpath, hasSubpath := strings.Cut(pattern, "#")
+	rest, _, hasQualifiers := strings.Cut(rest, "?")
+	p.hasSubpath = hasSubpath && subpath != ""
+	p.hasQualifiers = hasQualifiers
+
+	// The version follows the name, an @ before the last slash is part of
+	// the namespace, eg an npm scope.
+	p.hasVersion = strings.Contains(rest[strings.LastIndex(rest, "/")+1:], "@")
+
+	if strings.Contains(pattern, "*") {
+		// Canonicalize the pattern to match it against canonical purls. The
+		// asterisks are swapped with a placeholder as they are escaped.
+		canonical := pattern
+		if parsed, err := packageurl.FromString(strings.ReplaceAll(pattern, "*", globPlaceholder)); err == nil {
+			canonical = parsed.ToString()
+		}
+		expr := regexp.QuoteMeta(canonical)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, globPlaceholder, ".*")
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("compiling purl pattern: %w", err)
+		}
+		p.glob = re
+		return p, nil
+	}
+
+	parsed, err := packageurl.FromString(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("parsing purl pattern: %w", err)
+	}
+	p.purl = parsed.ToString()
+	return p, nil
+}
+
+// Match returns true if the package URL matches the pattern
+func (p *purlPattern) Match(purl sbom.PackageURL) bool {
+	if purl == "" {
+		return false
+	}
+
+	candidate := string(purl)
+	if parsed, err := packageurl.FromString(string(purl)); err == nil {
+		if !p.hasVersion {
+			parsed.Version = ""
+		}
+		if !p.hasQualifiers {
+			parsed.Qualifiers = nil
+		}
+		if !p.hasSubpath {
+			parsed.Subpath = ""
+		}
+		candidate = parsed.ToString()
+	}
+
+	if p.glob != nil {
+		return p.glob.MatchString(candidate)
+	}
+	return candidate == p.purl
+}
+
+// NodePurl returns the components of the node's package URL as a map. If
+// the node has no purl, all the components will be empty.
+//
+//	node.purl().namespace
+var NodePurl = func(lhs ref.Val) ref.Val {
+	n, ok := lhs.Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("purl() only applies to Node")
+	}
+
+	purl := packageurl.PackageURL{}
+	if n.Purl() != "" {
+		var err error
+		purl, err = packageurl.FromString(string(n.Purl()))
+		if err != nil {
+			return types.NewErr("parsing purl of node %q: %w", n.GetId(), err)
+		}
+	}
+
+	qualifiers := purl.Qualifiers.Map()
+	if qualifiers == nil {
+		qualifiers = map[string]string{}
+	}
+
+	return types.DefaultTypeAdapter.NativeToValue(map[string]any{
+		"type":       purl.Type,
+		"namespace":  purl.Namespace,
+		"name":       purl.Name,
+		"version":    purl.Version,
+		"qualifiers": qualifiers,
+		"subpath":    purl.Subpath,
+	})
+}
+
+// NodesByPurl returns a NodeList with the nodes whose package URL matches
+// a pattern. The pattern can be a full purl, a purl without version or a
+// glob with asterisks:
+//
+//	sbom.get_nodes_by_purl("pkg:golang/github.com/sigstore/*")
+var NodesByPurl = func(lhs, rhs ref.Val) ref.Val {
+	pattern, ok := rhs.Value().(string)
+	if !ok {
+		return types.NewErr("argument to get_nodes_by_purl must be a string")
+	}
+
+	nl, err := nodeListFromVal(lhs)
+	if err != nil {
+		return types.NewErr("get_nodes_by_purl: %w", err)
+	}
+
+	p, err := newPurlPattern(pattern)
+	if err != nil {
+		return types.NewErr("get_nodes_by_purl: %w", err)
+	}
+
+	return filterNodeList(nl, func(n *sbom.Node) bool {
+		return p.Match(n.Purl())
+	})
+}
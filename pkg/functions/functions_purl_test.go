@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+func TestPurlPattern(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		pattern string
+		purl    sbom.PackageURL
+		match   bool
+		mustErr bool
+	}{
+		{"exact", "pkg:golang/github.com/sigstore/cosign@2.0.0", "pkg:golang/github.com/sigstore/cosign@2.0.0", true, false},
+		{"exact-other-version", "pkg:golang/github.com/sigstore/cosign@2.0.0", "pkg:golang/github.com/sigstore/cosign@2.1.0", false, false},
+		{"exact-escaped", "pkg:golang/github.com/docker/cli@24.0.0+incompatible", "pkg:golang/github.com/docker/cli@24.0.0%2Bincompatible", true, false},
+		{"versionless", "pkg:golang/github.com/sigstore/cosign", "pkg:golang/github.com/sigstore/cosign@2.0.0", true, false},
+		{"versionless-qualifiers", "pkg:deb/debian/curl", "pkg:deb/debian/curl@7.0?arch=amd64", true, false},
+		{"qualifiers", "pkg:deb/debian/curl?arch=arm64", "pkg:deb/debian/curl@7.0?arch=amd64", false, false},
+		{"versionless-other", "pkg:golang/github.com/sigstore/cosign", "pkg:golang/github.com/sigstore/rekor@1.0.0", false, false},
+		{"glob", "pkg:golang/github.com/sigstore/*", "pkg:golang/github.com/sigstore/cosign/v2@2.0.0", true, false},
+		{"glob-other", "pkg:golang/github.com/sigstore/*", "pkg:golang/github.com/google/go-cmp@0.5.0", false, false},
+		{"glob-version", "pkg:npm/*@1.0.0", "pkg:npm/left-pad@1.0.0", true, false},
+		{"glob-version-other", "pkg:npm/*@1.0.0", "pkg:npm/left-pad@1.0.1", false, false},
+		{"spdx-slash", "pkg:npm/left-pad", "pkg:/npm/left-pad@1.0.0", true, false},
+		{"npm-scope", "pkg:npm/@nodelib/fs.stat", "pkg:npm/%40nodelib/fs.stat@2.0.5", true, false},
+		{"npm-scope-version", "pkg:npm/@nodelib/fs.stat@2.0.5", "pkg:npm/%40nodelib/fs.stat@2.0.5", true, false},
+		{"npm-scope-other-version", "pkg:npm/@nodelib/fs.stat@2.0.4", "pkg:npm/%40nodelib/fs.stat@2.0.5", false, false},
+		{"npm-scope-glob", "pkg:npm/@nodelib/*", "pkg:npm/%40nodelib/fs.walk@1.2.8", true, false},
+		{"npm-scope-escaped-glob", "pkg:npm/%40nodelib/*", "pkg:npm/@nodelib/fs.walk@1.2.8", true, false},
+		{"npm-scope-glob-other", "pkg:npm/@nodelib/*", "pkg:npm/%40babel/core@7.0.0", false, false},
+		{"no-purl", "pkg:npm/*", "", false, false},
+		{"invalid", "npm/left-pad", "", false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newPurlPattern(tc.pattern)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.match, p.Match(tc.purl))
+		})
+	}
+}
+
+func TestNodePurl(t *testing.T) {
+	res := NodePurl(&elements.Node{Node: purlNode("a", "pkg:deb/debian/curl@7.0?arch=amd64#src/lib")})
+	require.False(t, types.IsError(res), res)
+	m, ok := res.(traits.Mapper)
+	require.True(t, ok)
+	for k, v := range map[string]string{
+		"type": "deb", "namespace": "debian", "name": "curl", "version": "7.0", "subpath": "src/lib",
+	} {
+		require.Equal(t, types.String(v), m.Get(types.String(k)), k)
+	}
+	q, ok := m.Get(types.String("qualifiers")).(traits.Mapper)
+	require.True(t, ok)
+	require.Equal(t, types.String("amd64"), q.Get(types.String("arch")))
+
+	res = NodePurl(&elements.Node{Node: &sbom.Node{Id: "nopurl"}})
+	require.False(t, types.IsError(res), res)
+	m, ok = res.(traits.Mapper)
+	require.True(t, ok)
+	require.Equal(t, types.String(""), m.Get(types.String("name")))
+
+	res = NodePurl(&elements.Node{Node: purlNode("a", "not a purl")})
+	require.True(t, types.IsError(res))
+}
+
+func TestNodesByPurl(t *testing.T) {
+	nl := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				purlNode("app", "pkg:golang/example.com/app@1.0.0"),
+				purlNode("cosign", "pkg:golang/github.com/sigstore/cosign@2.0.0"),
+				purlNode("rekor", "pkg:golang/github.com/sigstore/rekor@1.0.0"),
+				purlNode("cmp", "pkg:golang/github.com/google/go-cmp@0.5.0"),
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "app", To: []string{"cosign", "cmp"}},
+				{Type: sbom.Edge_dependsOn, From: "cosign", To: []string{"rekor", "cmp"}},
+			},
+			RootElements: []string{"app"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		pattern  string
+		expected []string
+		roots    []string
+		edges    int
+	}{
+		{"glob", "pkg:golang/github.com/sigstore/*", []string{"cosign", "rekor"}, []string{"cosign"}, 1},
+		{"versionless", "pkg:golang/github.com/google/go-cmp", []string{"cmp"}, []string{"cmp"}, 0},
+		{"exact", "pkg:golang/github.com/sigstore/rekor@1.0.0", []string{"rekor"}, []string{"rekor"}, 0},
+		{"none", "pkg:golang/github.com/sigstore/rekor@2.0.0", []string{}, []string{}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NodesByPurl(nl, types.String(tc.pattern))
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, tc.expected, fragmentIDs(t, res))
+			got, ok := res.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Equal(t, tc.roots, got.RootElements)
+			require.Len(t, got.Edges, tc.edges)
+		})
+	}
+
+	// The source nodelist must remain untouched
+	require.Len(t, nl.Edges[1].To, 2)
+
+	res := NodesByPurl(nl, types.String("golang/*"))
+	require.True(t, types.IsError(res))
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+// Node matching modes supported by the set operations
+const (
+	MatchByID   = "id"
+	MatchByPurl = "purl"
+)
+
+// nodeKeyFunc returns the key used to match a node across nodelists. An
+// empty key means the node cannot be matched.
+type nodeKeyFunc func(*sbom.Node) string
+
+// nodeKeyFuncFromMode returns the key function for a matching mode
+func nodeKeyFuncFromMode(mode string) (nodeKeyFunc, error) {
+	switch strings.ToLower(mode) {
+	case MatchByID:
+		return func(n *sbom.Node) string { return n.GetId() }, nil
+	case MatchByPurl:
+		return func(n *sbom.Node) string { return string(n.Purl()) }, nil
+	default:
+		return nil, fmt.Errorf("invalid node matching mode %q (must be %q or %q)", mode, MatchByID, MatchByPurl)
+	}
+}
+
+// setOperation defines which nodes survive an operation between two nodelists
+type setOperation struct {
+	// keepLeft is called with each node in the left nodelist and returns
+	// true if the node is to be kept in the result.
+	keepLeft func(matched bool) bool
+
+	// keepRight determines if nodes in the right nodelist that have no
+	// match in the left are added to the result.
+	keepRight bool
+}
+
+var (
+	opUnion = setOperation{
+		keepLeft:  func(bool) bool { return true },
+		keepRight: true,
+	}
+	opIntersection = setOperation{
+		keepLeft:  func(matched bool) bool { return matched },
+		keepRight: false,
+	}
+	opDifference = setOperation{
+		keepLeft:  func(matched bool) bool { return !matched },
+		keepRight: false,
+	}
+	opSymmetricDifference = setOperation{
+		keepLeft:  func(matched bool) bool { return !matched },
+		keepRight: true,
+	}
+)
+
+// apply runs the set operation and returns a new NodeList with copies of the
+// surviving nodes. Nodes in rhs that match a node in lhs are represented by
+// the lhs node and the edges from rhs are rewritten to point to it. Unmatched
+// nodes in rhs reusing an ID from lhs get a new ID. Only edges between
+// surviving nodes are kept and the root elements are recomputed.
+func (op *setOperation) apply(lhs, rhs *sbom.NodeList, key nodeKeyFunc) *elements.NodeList {
+	lhsKeys := map[string]string{}
+	lhsIDs := map[string]struct{}{}
+	for _, n := range lhs.GetNodes() {
+		lhsIDs[n.GetId()] = struct{}{}
+		if k := key(n); k != "" {
+			if _, ok := lhsKeys[k]; !ok {
+				lhsKeys[k] = n.GetId()
+			}
+		}
+	}
+
+	rhsKeys := map[string]struct{}{}
+	rhsIDs := map[string]struct{}{}
+	matches := map[string]string{}
+	for _, n := range rhs.GetNodes() {
+		rhsIDs[n.GetId()] = struct{}{}
+		k := key(n)
+		if k == "" {
+			continue
+		}
+		rhsKeys[k] = struct{}{}
+		if id, ok := lhsKeys[k]; ok {
+			matches[n.GetId()] = id
+		}
+	}
+
+	// Unmatched rhs nodes reusing an ID from lhs are a different node, they
+	// are renamed so that their edges are not mixed with the lhs ones.
+	renamed := map[string]string{}
+	for _, n := range rhs.GetNodes() {
+		id := n.GetId()
+		if _, ok := matches[id]; ok {
+			continue
+		}
+		if _, ok := lhsIDs[id]; !ok {
+			continue
+		}
+		if _, ok := renamed[id]; ok {
+			continue
+		}
+		for i := 1; ; i++ {
+			newID := fmt.Sprintf("%s-%d", id, i)
+			_, inLHS := lhsIDs[newID]
+			_, inRHS := rhsIDs[newID]
+			if !inLHS && !inRHS {
+				renamed[id] = newID
+				lhsIDs[newID] = struct{}{}
+				break
+			}
+		}
+	}
+
+	ret := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{},
+		},
+	}
+
+	seen := map[string]struct{}{}
+	for _, n := range lhs.GetNodes() {
+		_, matched := rhsKeys[key(n)]
+		if !op.keepLeft(matched) {
+			continue
+		}
+		if _, ok := seen[n.GetId()]; ok {
+			continue
+		}
+		seen[n.GetId()] = struct{}{}
+		ret.AddNode(n.Copy())
+	}
+
+	translate := func(id string) string {
+		if newID, ok := matches[id]; ok {
+			return newID
+		}
+		if newID, ok := renamed[id]; ok {
+			return newID
+		}
+		return id
+	}
+
+	if op.keepRight {
+		for _, n := range rhs.GetNodes() {
+			if _, matched := matches[n.GetId()]; matched {
+				continue
+			}
+			if _, ok := seen[translate(n.GetId())]; ok {
+				continue
+			}
+			seen[translate(n.GetId())] = struct{}{}
+			nn := n.Copy()
+			nn.Id = translate(n.GetId())
+			ret.AddNode(nn)
+		}
+	}
+
+	for _, e := range lhs.GetEdges() {
+		ret.AddEdge(e.GetFrom(), e.GetType(), append([]string{}, e.GetTo()...))
+	}
+	for _, e := range rhs.GetEdges() {
+		tos := make([]string, 0, len(e.GetTo()))
+		for _, to := range e.GetTo() {
+			tos = append(tos, translate(to))
+		}
+		ret.AddEdge(translate(e.GetFrom()), e.GetType(), tos)
+	}
+
+	ret.RootElements = append(ret.RootElements, lhs.GetRootElements()...)
+	for _, id := range rhs.GetRootElements() {
+		ret.RootElements = append(ret.RootElements, translate(id))
+	}
+
+	cleanEdges(ret)
+	cleanRootElements(ret)
+	reconnectOrphanNodes(ret)
+	return ret
+}
+
+// setOperationBinding returns a function binding that runs a set operation
+// between two Documents or NodeLists. The binding takes an optional third
+// argument to define the node matching mode (id or purl).
+func setOperationBinding(name string, op setOperation) func(...ref.Val) ref.Val {
+	return func(vals ...ref.Val) ref.Val {
+		if len(vals) != 2 && len(vals) != 3 {
+			return types.NewErr("invalid number of arguments for %s", name)
+		}
+
+		mode := MatchByID
+		if len(vals) == 3 {
+			m, ok := vals[2].Value().(string)
+			if !ok {
+				return types.NewErr("%s matching mode must be a string", name)
+			}
+			mode = m
+		}
+
+		key, err := nodeKeyFuncFromMode(mode)
+		if err != nil {
+			return types.NewErr("%s: %w", name, err)
+		}
+
+		lhs, err := nodeListFromVal(vals[0])
+		if err != nil {
+			return types.NewErr("%s: %w", name, err)
+		}
+
+		rhs, err := nodeListFromVal(vals[1])
+		if err != nil {
+			return types.NewErr("%s: %w", name, err)
+		}
+
+		return op.apply(lhs, rhs, key)
+	}
+}
+
+// Union returns a new NodeList with the nodes of both elements. Nodes
+// present in both are only included once.
+var Union = setOperationBinding("union", opUnion)
+
+// Intersection returns a new NodeList with the nodes of the first element
+// that are also present in the second.
+var Intersection = setOperationBinding("intersect", opIntersection)
+
+// Difference returns a new NodeList with the nodes of the first element that
+// are not present in the second.
+var Difference = setOperationBinding("difference", opDifference)
+
+// SymmetricDifference returns a new NodeList with the nodes that are present
+// in only one of the elements.
+var SymmetricDifference = setOperationBinding("symmetric_difference", opSymmetricDifference)
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+func purlNode(id, purl string) *sbom.Node {
+	return &sbom.Node{
+		Id:          id,
+		Identifiers: map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): purl},
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	// lhs: a -> b -> c
+	lhs := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				purlNode("a", "pkg:golang/a@1.0.0"),
+				purlNode("b", "pkg:golang/b@1.0.0"),
+				purlNode("c", "pkg:golang/c@1.0.0"),
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "a", To: []string{"b"}},
+				{Type: sbom.Edge_dependsOn, From: "b", To: []string{"c"}},
+			},
+			RootElements: []string{"a"},
+		},
+	}
+
+	// rhs, generated by another tool: x -> y -> z, where x and y have the
+	// same purls as a and b.
+	rhs := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				purlNode("x", "pkg:golang/a@1.0.0"),
+				purlNode("y", "pkg:golang/b@1.0.0"),
+				purlNode("b", "pkg:golang/other@1.0.0"),
+				purlNode("z", "pkg:golang/z@1.0.0"),
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "x", To: []string{"y"}},
+				{Type: sbom.Edge_dependsOn, From: "y", To: []string{"z"}},
+			},
+			RootElements: []string{"x"},
+		},
+	}
+
+	nodeIDs := func(t *testing.T, v ref.Val) []string {
+		t.Helper()
+		require.False(t, types.IsError(v), v)
+		nl, ok := v.Value().(*sbom.NodeList)
+		require.True(t, ok)
+		ids := []string{}
+		for _, n := range nl.Nodes {
+			ids = append(ids, n.Id)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	for _, tc := range []struct {
+		name     string
+		fn       func(...ref.Val) ref.Val
+		mode     string
+		expected []string
+		roots    []string
+		edges    int
+	}{
+		{"union-id", Union, MatchByID, []string{"a", "b", "c", "x", "y", "z"}, []string{"a", "x"}, 4},
+		{"union-purl", Union, MatchByPurl, []string{"a", "b", "b-1", "c", "z"}, []string{"a", "b-1"}, 2},
+		{"intersect-id", Intersection, MatchByID, []string{"b"}, []string{"b"}, 0},
+		{"intersect-purl", Intersection, MatchByPurl, []string{"a", "b"}, []string{"a"}, 1},
+		{"difference-id", Difference, MatchByID, []string{"a", "c"}, []string{"a", "c"}, 0},
+		{"difference-purl", Difference, MatchByPurl, []string{"c"}, []string{"c"}, 0},
+		{"symdiff-id", SymmetricDifference, MatchByID, []string{"a", "c", "x", "y", "z"}, []string{"a", "x", "c"}, 2},
+		{"symdiff-purl", SymmetricDifference, MatchByPurl, []string{"b-1", "c", "z"}, []string{"c", "b-1", "z"}, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.fn(lhs, rhs, types.String(tc.mode))
+			require.Equal(t, tc.expected, nodeIDs(t, res))
+			nl, ok := res.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Equal(t, tc.roots, nl.RootElements)
+			require.Len(t, nl.Edges, tc.edges)
+		})
+	}
+
+	// The union in purl mode rewrites the rhs edges to the lhs nodes
+	res := Union(lhs, rhs, types.String(MatchByPurl))
+	nl, ok := res.Value().(*sbom.NodeList)
+	require.True(t, ok)
+	require.Equal(t, []string{"c", "z"}, nl.GetEdgeByType("b", sbom.Edge_dependsOn).To)
+
+	// Unmatched rhs nodes reusing an lhs ID are renamed along with their edges
+	colliding := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				purlNode("x", "pkg:golang/a@1.0.0"),
+				purlNode("b", "pkg:golang/other@1.0.0"),
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "x", To: []string{"b"}},
+			},
+			RootElements: []string{"x"},
+		},
+	}
+	res = Union(lhs, colliding, types.String(MatchByPurl))
+	nl, ok = res.Value().(*sbom.NodeList)
+	require.True(t, ok)
+	require.Equal(t, sbom.PackageURL("pkg:golang/other@1.0.0"), nl.GetNodeByID("b-1").Purl())
+	require.Equal(t, sbom.PackageURL("pkg:golang/b@1.0.0"), nl.GetNodeByID("b").Purl())
+	require.Equal(t, []string{"b", "b-1"}, nl.GetEdgeByType("a", sbom.Edge_dependsOn).To)
+	require.Equal(t, []string{"b"}, nodeIDs(t, Difference(colliding, lhs, types.String(MatchByPurl))))
+
+	// The default mode is by ID
+	require.Equal(t, []string{"b"}, nodeIDs(t, Intersection(lhs, rhs)))
+
+	// Documents are supported too
+	doc := &elements.Document{Document: &sbom.Document{NodeList: rhs.NodeList}}
+	require.Equal(t, []string{"a", "c"}, nodeIDs(t, Difference(lhs, doc)))
+
+	// Invalid modes return an error
+	require.True(t, types.IsError(Union(lhs, rhs, types.String("name"))))
+
+	// The operands are not modified
+	require.Len(t, lhs.Nodes, 3)
+	require.Len(t, lhs.Edges, 2)
+	require.Equal(t, []string{"a"}, lhs.RootElements)
+	require.Equal(t, []string{"y"}, rhs.Edges[0].To)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/google/cel-go/common/types/ref"
 	"github.com/protobom/protobom/pkg/sbom"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/protobom/cel/pkg/elements"
 )
@@ -97,3 +98,187 @@ func TestNodeByID(t *testing.T) {
 		})
 	}
 }
+
+func TestAddition(t *testing.T) {
+	lhs := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				{Id: "node1"}, {Id: "node2"},
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "node1", To: []string{"node2"}},
+			},
+			RootElements: []string{"node1"},
+		},
+	}
+	rhs := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes: []*sbom.Node{
+				{Id: "node2"}, {Id: "node3"}, {Id: "node4"},
+			},
+			Edges: []*sbom.Edge{
+				{Type: sbom.Edge_dependsOn, From: "node1", To: []string{"node3"}},
+				{Type: sbom.Edge_contains, From: "node3", To: []string{"node4"}},
+			},
+			RootElements: []string{"node1", "node3"},
+		},
+	}
+
+	res := Addition(lhs, rhs)
+	require.Equal(t, "*elements.NodeList", fmt.Sprintf("%T", res), res)
+	nl, ok := res.Value().(*sbom.NodeList)
+	require.True(t, ok)
+
+	require.Len(t, nl.Nodes, 4)
+	require.Equal(t, []string{"node1", "node3"}, nl.RootElements)
+	require.Len(t, nl.Edges, 2)
+	require.Equal(t, []string{"node2", "node3"}, nl.GetEdgeByType("node1", sbom.Edge_dependsOn).To)
+	require.Equal(t, []string{"node4"}, nl.GetEdgeByType("node3", sbom.Edge_contains).To)
+
+	// Operands must not be modified
+	require.Len(t, lhs.Nodes, 2)
+	require.Equal(t, []string{"node2"}, lhs.Edges[0].To)
+	require.Equal(t, []string{"node1"}, lhs.RootElements)
+
+	// Unsupported types return an error
+	require.True(t, types.IsError(Addition(types.String("a"), rhs)))
+}
+
+func TestRelateNodeListAtID(t *testing.T) {
+	newDoc := func() *elements.Document {
+		return &elements.Document{
+			Document: &sbom.Document{
+				NodeList: &sbom.NodeList{
+					Nodes:        []*sbom.Node{{Id: "root"}},
+					Edges:        []*sbom.Edge{},
+					RootElements: []string{"root"},
+				},
+			},
+		}
+	}
+	nl := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{{Id: "tool"}},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{"tool"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		relType  string
+		expected sbom.Edge_Type
+		mustErr  bool
+	}{
+		{"depends-on", "DEPENDS_ON", sbom.Edge_dependsOn, false},
+		{"contains", "CONTAINS", sbom.Edge_contains, false},
+		{"build-tool", "BUILD_TOOL_OF", sbom.Edge_buildTool, false},
+		{"dev-dependency-protobom", "devDependency", sbom.Edge_devDependency, false},
+		{"unknown", "IS_FRIENDS_WITH", sbom.Edge_UNKNOWN, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			original := newDoc()
+			res := RelateNodeListAtID(original, nl, types.String("root"), types.String(tc.relType))
+			if tc.mustErr {
+				require.True(t, types.IsError(res))
+				return
+			}
+			require.False(t, types.IsError(res), res)
+			doc, ok := res.Value().(*sbom.Document)
+			require.True(t, ok)
+			require.Len(t, doc.NodeList.Edges, 1)
+			require.Equal(t, tc.expected, doc.NodeList.Edges[0].Type)
+			require.Equal(t, []string{"tool"}, doc.NodeList.Edges[0].To)
+
+			// The arguments are not modified
+			require.True(t, proto.Equal(newDoc().Document, original.Document))
+			require.Len(t, nl.Nodes, 1)
+			require.Empty(t, nl.Edges)
+		})
+	}
+}
+
+func TestFunctionsDoNotModifyArguments(t *testing.T) {
+	newDoc := func() *sbom.Document {
+		return &sbom.Document{
+			Metadata: &sbom.Metadata{Id: "doc"},
+			NodeList: &sbom.NodeList{
+				Nodes: []*sbom.Node{
+					{Id: "root", Type: sbom.Node_PACKAGE},
+					{Id: "lib", Type: sbom.Node_PACKAGE},
+					{Id: "file", Type: sbom.Node_FILE},
+					{Id: "orphan", Type: sbom.Node_FILE},
+				},
+				Edges: []*sbom.Edge{
+					{Type: sbom.Edge_contains, From: "root", To: []string{"file", "lib"}},
+					{Type: sbom.Edge_dependsOn, From: "lib", To: []string{"file"}},
+				},
+				RootElements: []string{"root"},
+			},
+		}
+	}
+	tool := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{{Id: "tool"}},
+			RootElements: []string{"tool"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		fn   func(ref.Val) ref.Val
+	}{
+		{"files", Files},
+		{"packages", Packages},
+		{"to-document", func(v ref.Val) ref.Val {
+			return ToDocument(&elements.NodeList{NodeList: v.Value().(*sbom.Document).GetNodeList()})
+		}},
+		{"relate", func(v ref.Val) ref.Val {
+			return RelateNodeListAtID(v, tool, types.String("lib"), types.String("DEPENDS_ON"))
+		}},
+		{"relate-nodelist", func(v ref.Val) ref.Val {
+			return RelateNodeListAtID(&elements.NodeList{NodeList: v.Value().(*sbom.Document).GetNodeList()}, tool, types.String("root"), types.String("CONTAINS"))
+		}},
+		{"union", func(v ref.Val) ref.Val { return Union(v, v) }},
+		{"with-version", func(v ref.Val) ref.Val {
+			return NodeWithVersion(NodeByID(v, types.String("lib")), types.String("2.0"))
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := newDoc()
+			for range 2 {
+				res := tc.fn(&elements.Document{Document: doc})
+				require.False(t, types.IsError(res), res)
+				require.True(t, proto.Equal(newDoc(), doc), "document was modified")
+			}
+			require.Len(t, tool.Nodes, 1)
+			require.Empty(t, tool.Edges)
+		})
+	}
+}
+
+func TestSelectNodes(t *testing.T) {
+	nl := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{{Id: "root"}, {Id: "lib"}, {Id: "dep"}},
+			Edges:        []*sbom.Edge{{Type: sbom.Edge_dependsOn, From: "root", To: []string{"lib"}}, {Type: sbom.Edge_dependsOn, From: "lib", To: []string{"dep"}}},
+			RootElements: []string{"root"},
+		},
+	}
+	selected := types.NewRefValList(types.DefaultTypeAdapter, []ref.Val{
+		&elements.Node{Node: &sbom.Node{Id: "lib"}},
+		&elements.Node{Node: &sbom.Node{Id: "dep"}},
+	})
+
+	res := SelectNodes(nl, selected)
+	require.False(t, types.IsError(res), res)
+	ret, ok := res.Value().(*sbom.NodeList)
+	require.True(t, ok)
+	require.Len(t, ret.Nodes, 2)
+	require.Len(t, ret.Edges, 1)
+	require.Equal(t, "lib", ret.Edges[0].From)
+	require.Equal(t, []string{"lib"}, ret.RootElements)
+
+	require.True(t, types.IsError(SelectNodes(nl, types.String("lib"))))
+	require.True(t, types.IsError(SelectNodes(nl, types.NewStringList(types.DefaultTypeAdapter, []string{"lib"}))))
+}
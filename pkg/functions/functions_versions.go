@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/package-url/packageurl-go"
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+// versConstraint is one of the constraints in a vers range
+type versConstraint struct {
+	comparator string
+	version    string
+}
+
+// versRange is a version range expressed using the purl vers syntax:
+//
+//	vers:<scheme>/<comparator><version>|<comparator><version>...
+//
+// The scheme is optional to let the caller infer it.
+type versRange struct {
+	scheme      string
+	any         bool
+	constraints []versConstraint
+}
+
+// versComparators lists the comparators in the order they need to be tried
+var versComparators = []string{">=", "<=", "!=", "<", ">", "="}
+
+// parseVersRange parses a vers range string. The vers: prefix and scheme
+// may be omitted.
+func parseVersRange(s string) (*versRange, error) {
+	s = strings.TrimSpace(s)
+	ret := &versRange{}
+
+	if rest, ok := strings.CutPrefix(s, "vers:"); ok {
+		scheme, constraints, ok := strings.Cut(rest, "/")
+		if !ok || scheme == "" {
+			return nil, fmt.Errorf("invalid version range %q: missing versioning scheme", s)
+		}
+		ret.scheme = strings.ToLower(scheme)
+		s = constraints
+	}
+
+	s = strings.Join(strings.Fields(s), "")
+	if s == "" {
+		return nil, errors.New("version range has no constraints")
+	}
+
+	if s == "*" {
+		ret.any = true
+		return ret, nil
+	}
+
+	for _, c := range strings.Split(s, "|") {
+		if c == "" {
+			return nil, fmt.Errorf("invalid version range %q: empty constraint", s)
+		}
+		constraint := versConstraint{comparator: "="}
+		for _, cmp := range versComparators {
+			if v, ok := strings.CutPrefix(c, cmp); ok {
+				constraint.comparator = cmp
+				c = v
+				break
+			}
+		}
+		v, err := url.PathUnescape(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version in range: %w", err)
+		}
+		if v == "" {
+			return nil, fmt.Errorf("invalid version range %q: constraint without version", s)
+		}
+		constraint.version = v
+		ret.constraints = append(ret.constraints, constraint)
+	}
+	return ret, nil
+}
+
+// Contains checks if a version is in the range following the algorithm
+// defined in the vers specification.
+func (r *versRange) Contains(version string, compare versionCompareFunc) (bool, error) {
+	if r.any {
+		return true, nil
+	}
+
+	// Equality checks go first
+	for _, c := range r.constraints {
+		res, err := compare(version, c.version)
+		if err != nil {
+			return false, err
+		}
+		if res != 0 {
+			continue
+		}
+		switch c.comparator {
+		case "=", "<=", ">=":
+			return true, nil
+		case "!=":
+			return false, nil
+		}
+	}
+
+	// Now check the intervals, sorted by version
+	intervals := []versConstraint{}
+	for _, c := range r.constraints {
+		if c.comparator != "=" && c.comparator != "!=" {
+			intervals = append(intervals, c)
+		}
+	}
+	var sortErr error
+	slices.SortStableFunc(intervals, func(a, b versConstraint) int {
+		res, err := compare(a.version, b.version)
+		if err != nil {
+			sortErr = err
+		}
+		return res
+	})
+	if sortErr != nil {
+		return false, sortErr
+	}
+
+	isLess := func(c versConstraint) bool { return c.comparator == "<" || c.comparator == "<=" }
+	isGreater := func(c versConstraint) bool { return c.comparator == ">" || c.comparator == ">=" }
+
+	for i, c := range intervals {
+		res, err := compare(version, c.version)
+		if err != nil {
+			return false, err
+		}
+		if i == 0 && isLess(c) && res < 0 {
+			return true, nil
+		}
+		if i == len(intervals)-1 && isGreater(c) && res > 0 {
+			return true, nil
+		}
+		if i < len(intervals)-1 && isGreater(c) && isLess(intervals[i+1]) && res > 0 {
+			next, err := compare(version, intervals[i+1].version)
+			if err != nil {
+				return false, err
+			}
+			if next < 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// nodeVersionScheme returns the versioning scheme of a node inferred from
+// its package URL type. It defaults to semver.
+func nodeVersionScheme(n *sbom.Node) string {
+	if n.Purl() != "" {
+		if p, err := packageurl.FromString(string(n.Purl())); err == nil {
+			if _, err := versionComparator(p.Type); err == nil {
+				return p.Type
+			}
+		}
+	}
+	return VersionSchemeSemver
+}
+
+// nodeVersion returns the version of a node, falling back to the version
+// in its package URL.
+func nodeVersion(n *sbom.Node) string {
+	if n.GetVersion() != "" {
+		return n.GetVersion()
+	}
+	if n.Purl() != "" {
+		if p, err := packageurl.FromString(string(n.Purl())); err == nil {
+			return p.Version
+		}
+	}
+	return ""
+}
+
+// CompareVersionsBinding compares two version strings:
+//
+//	protobom.compare_versions("1.10.0", "1.9.0", "semver") // 1
+var CompareVersionsBinding = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 4 {
+		return types.NewErr("incorrect number of params")
+	}
+	args := make([]string, 0, 3)
+	for _, v := range vals[1:] {
+		s, ok := v.Value().(string)
+		if !ok {
+			return types.NewErr("compare_versions arguments must be strings, not %T", v.Value())
+		}
+		args = append(args, s)
+	}
+
+	res, err := CompareVersions(args[0], args[1], args[2])
+	if err != nil {
+		return types.NewErr("compare_versions: %w", err)
+	}
+	return types.Int(res)
+}
+
+// NodeVersionInRange checks if the version of a node is in a range expressed
+// in vers syntax. If the range does not specify a versioning scheme, it is
+// inferred from the node's package URL type. Nodes without a version or with
+// a version that is not valid in the scheme are never in the range, so
+// ranges can be checked on all the nodes of an SBOM.
+//
+//	node.version_in_range("vers:golang/>=1.2.0|<1.2.5")
+//	node.version_in_range(">=1.2.0|<1.2.5")
+var NodeVersionInRange = func(lhs, rhs ref.Val) ref.Val {
+	n, ok := lhs.Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("version_in_range() only applies to Node")
+	}
+	rangeString, ok := rhs.Value().(string)
+	if !ok {
+		return types.NewErr("version range must be a string, not %T", rhs.Value())
+	}
+
+	r, err := parseVersRange(rangeString)
+	if err != nil {
+		return types.NewErr("version_in_range: %w", err)
+	}
+
+	scheme := r.scheme
+	if scheme == "" {
+		scheme = nodeVersionScheme(n)
+	}
+	compare, err := versionComparator(scheme)
+	if err != nil {
+		return types.NewErr("version_in_range: %w", err)
+	}
+
+	version := nodeVersion(n)
+	if version == "" {
+		return types.False
+	}
+
+	// Check the node version parses so that errors comparing it come
+	// from the range versions.
+	if _, err := compare(version, version); err != nil {
+		return types.False
+	}
+
+	in, err := r.Contains(version, compare)
+	if err != nil {
+		return types.NewErr("version_in_range: checking %q: %w", n.GetId(), err)
+	}
+	return types.Bool(in)
+}
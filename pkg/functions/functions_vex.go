@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/elements"
+	"github.com/protobom/cel/pkg/vex"
+)
+
+// LoadVEX reads an OpenVEX document from a file:
+//
+//	protobom.load_vex("/path/to/document.openvex.json")
+var LoadVEX = func(_, pathVal ref.Val) ref.Val {
+	path, ok := pathVal.Value().(string)
+	if !ok {
+		return types.NewErr("load_vex: path must be a string")
+	}
+
+	doc, err := vex.Load(path)
+	if err != nil {
+		return types.NewErr("load_vex: %w", err)
+	}
+	return &elements.VEX{Document: doc}
+}
+
+// ParseVEX reads an OpenVEX document from a string:
+//
+//	protobom.parse_vex('{"@context": "https://openvex.dev/ns/v0.2.0", ...}')
+var ParseVEX = func(_, dataVal ref.Val) ref.Val {
+	data, ok := dataVal.Value().(string)
+	if !ok {
+		return types.NewErr("parse_vex: document must be a string")
+	}
+
+	doc, err := vex.Parse([]byte(data))
+	if err != nil {
+		return types.NewErr("parse_vex: %w", err)
+	}
+	return &elements.VEX{Document: doc}
+}
+
+// nodeIdentifiers returns the software identifiers of a node, its purl
+// among them, used to match it against VEX products.
+func nodeIdentifiers(n *sbom.Node) []string {
+	ret := make([]string, 0, len(n.GetIdentifiers()))
+	for _, id := range n.GetIdentifiers() {
+		ret = append(ret, id)
+	}
+	return ret
+}
+
+// VEXStatusFor returns the status of a vulnerability in a node according to
+// a VEX document. The node is matched against the VEX products by its purl
+// and the rest of its identifiers. Returns an empty string if no statement
+// applies to the node:
+//
+//	vex.status_for(node, "CVE-2023-44270")
+var VEXStatusFor = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 3 {
+		return types.NewErr("status_for takes two arguments")
+	}
+	doc, ok := vals[0].Value().(*vex.Document)
+	if !ok {
+		return types.NewErr("status_for() only applies to VEX documents")
+	}
+	n, ok := vals[1].Value().(*sbom.Node)
+	if !ok {
+		return types.NewErr("status_for: first argument must be a Node")
+	}
+	vulnID, ok := vals[2].Value().(string)
+	if !ok {
+		return types.NewErr("status_for: vulnerability ID must be a string")
+	}
+
+	return types.String(doc.StatusFor(vulnID, nodeIdentifiers(n)...))
+}
+
+// WithoutNotAffected returns the nodes of a Document or NodeList except
+// those that a VEX document states are not affected by a vulnerability:
+//
+//	sboms[0].without_not_affected(vex, "CVE-2023-44270")
+var WithoutNotAffected = func(vals ...ref.Val) ref.Val {
+	if len(vals) != 3 {
+		return types.NewErr("without_not_affected takes two arguments")
+	}
+	nl, err := nodeListFromVal(vals[0])
+	if err != nil {
+		return types.NewErr("without_not_affected: %w", err)
+	}
+	doc, ok := vals[1].Value().(*vex.Document)
+	if !ok {
+		return types.NewErr("without_not_affected: first argument must be a VEX document")
+	}
+	vulnID, ok := vals[2].Value().(string)
+	if !ok {
+		return types.NewErr("without_not_affected: vulnerability ID must be a string")
+	}
+
+	return filterNodeList(nl, func(n *sbom.Node) bool {
+		return doc.StatusFor(vulnID, nodeIdentifiers(n)...) != vex.StatusNotAffected
+	})
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+func testVEX(t *testing.T) ref.Val {
+	t.Helper()
+	data, err := os.ReadFile("../vex/testdata/bom.openvex.json")
+	require.NoError(t, err)
+	v := ParseVEX(&elements.Protobom{}, types.String(data))
+	require.False(t, types.IsError(v), v)
+	return v
+}
+
+func TestVEXStatusFor(t *testing.T) {
+	v := testVEX(t)
+	for _, tc := range []struct {
+		name     string
+		node     *sbom.Node
+		vulnID   string
+		expected string
+	}{
+		{"purl", osvTestNode("braces", "pkg:npm/braces@3.0.2", "3.0.2"), "CVE-2024-4068", "not_affected"},
+		{"identifiers", osvTestNode("crypto", "pkg:golang/golang.org/x/crypto@0.11.0", "0.11.0"), "GO-2023-2402", "not_affected"},
+		{"no-statement", osvTestNode("braces", "pkg:npm/braces@3.0.3", "3.0.3"), "CVE-2024-4068", ""},
+		{"no-identifiers", &sbom.Node{Id: "braces"}, "CVE-2024-4068", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := VEXStatusFor(v, &elements.Node{Node: tc.node}, types.String(tc.vulnID))
+			require.Equal(t, types.String(tc.expected), res)
+		})
+	}
+
+	require.True(t, types.IsError(VEXStatusFor(v, types.String("braces"), types.String("CVE-2024-4068"))))
+	require.True(t, types.IsError(ParseVEX(&elements.Protobom{}, types.String("{}"))))
+	require.True(t, types.IsError(LoadVEX(&elements.Protobom{}, types.String("../vex/testdata/nope.json"))))
+}
+
+func TestWithoutNotAffected(t *testing.T) {
+	v := testVEX(t)
+	nl := osvTestNodeList()
+
+	res, ok := WithoutNotAffected(&elements.NodeList{NodeList: nl}, v, types.String("GHSA-grv7-fg5c-xmjg")).Value().(*sbom.NodeList)
+	require.True(t, ok)
+	ids := []string{}
+	for _, n := range res.GetNodes() {
+		ids = append(ids, n.GetId())
+	}
+	require.Equal(t, []string{"braces-fixed", "crypto", "micromatch", "no-purl"}, ids)
+	require.Len(t, nl.GetNodes(), 5)
+
+	require.True(t, types.IsError(WithoutNotAffected(&elements.NodeList{NodeList: nl}, types.String("vex"), types.String("CVE-2024-4068"))))
+}
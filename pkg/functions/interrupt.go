@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"errors"
+
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// ErrInterrupted is returned by the graph traversals when the evaluation
+// calling them is interrupted.
+var ErrInterrupted = errors.New("evaluation interrupted")
+
+// InterruptCheck reports if the evaluation calling a function has been
+// interrupted, for example because its context was canceled. Long graph
+// traversals call it as they walk and stop with ErrInterrupted.
+type InterruptCheck func() bool
+
+// InterruptibleFunction is a function binding that checks for interrupts
+type InterruptibleFunction func(InterruptCheck, ...ref.Val) ref.Val
+
+// neverInterrupted is the check used when a function is not called from an
+// interruptible evaluation.
+func neverInterrupted() bool {
+	return false
+}
+
+// InterruptibleBindings are the versions of the graph traversal functions
+// that check for interrupts, keyed by function name. Programs planned with
+// library.InterruptibleGraphFunctions call them instead of the regular
+// bindings.
+var InterruptibleBindings = map[string]InterruptibleFunction{
+	"get_node_descendants": nodeDescendants,
+	"get_paths":            nodePaths,
+	"get_shortest_path":    getShortestPath,
+	"get_node_ancestors":   nodeAncestors,
+	"get_nodes_at_depth":   nodesAtDepth,
+	"get_graph_at_depth":   graphAtDepth,
+	"depth_of":             depthOf,
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterruptibleBindings(t *testing.T) {
+	args := map[string][]ref.Val{
+		"get_node_descendants": {types.String("app"), types.Int(10)},
+		"get_paths":            {types.String("app"), types.String("log4j"), types.Int(10)},
+		"get_shortest_path":    {types.String("app"), types.String("log4j")},
+		"get_node_ancestors":   {types.String("log4j"), types.Int(10)},
+		"get_nodes_at_depth":   {types.Int(1)},
+		"get_graph_at_depth":   {types.Int(1)},
+		"depth_of":             {types.String("log4j")},
+	}
+	require.Len(t, args, len(InterruptibleBindings))
+
+	for name, impl := range InterruptibleBindings {
+		t.Run(name, func(t *testing.T) {
+			vals := append([]ref.Val{testGraph()}, args[name]...)
+
+			res := impl(neverInterrupted, vals...)
+			require.False(t, types.IsError(res), res)
+
+			res = impl(func() bool { return true }, vals...)
+			require.True(t, types.IsError(res))
+			err, ok := res.(*types.Err)
+			require.True(t, ok)
+			require.True(t, errors.Is(err, ErrInterrupted), err)
+		})
+	}
+}
+
+func TestNodeDescendants(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		id       string
+		maxDepth int64
+	}{
+		{"all", "app", 10},
+		{"depth-1", "app", 1},
+		{"depth-0", "app", 0},
+		{"cycle", "lib2", 10},
+		{"leaf", "log4j", 10},
+		{"not-found", "nope", 10},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			nl := testGraph()
+			res := NodeDescendants(nl, types.String(tc.id), types.Int(tc.maxDepth))
+			require.False(t, types.IsError(res), res)
+
+			// The interruptible version matches protobom
+			expected := nl.NodeDescendants(tc.id, int(tc.maxDepth))
+			got, ok := res.Value().(*sbom.NodeList)
+			require.True(t, ok)
+			require.Equal(t, expected.GetRootElements(), got.GetRootElements())
+			require.ElementsMatch(t, nodeIDs(expected), nodeIDs(got))
+			require.Len(t, got.GetEdges(), len(expected.GetEdges()))
+			for i := range got.GetEdges() {
+				require.Equal(t, sbom.Edge_ancestor, got.GetEdges()[i].GetType())
+				require.ElementsMatch(t, expected.GetEdges()[i].GetTo(), got.GetEdges()[i].GetTo())
+			}
+
+			// Nodes are returned in the order they are found
+			if len(got.GetNodes()) > 0 {
+				require.Equal(t, tc.id, got.GetNodes()[0].GetId())
+			}
+		})
+	}
+}
+
+func nodeIDs(nl *sbom.NodeList) []string {
+	ids := []string{}
+	for _, n := range nl.GetNodes() {
+		ids = append(ids, n.GetId())
+	}
+	return ids
+}
@@ -4,15 +4,23 @@
 package functions
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
 	"github.com/protobom/protobom/pkg/sbom"
 
 	"github.com/protobom/cel/pkg/elements"
 )
 
 // cleanEdges removes all edges that have broken Froms and removes
-// any destination IDs from elements not in the NodeList.
+// any destination IDs from elements not in the NodeList. The edges are
+// replaced by new ones, the original edge protos are never modified as they
+// may be shared with other NodeLists.
 func cleanEdges(nl *elements.NodeList) {
-	// First copy the nodelist edges
 	newEdges := []*sbom.Edge{}
 
 	// Build a catalog of the elements ids
@@ -38,8 +46,7 @@ func cleanEdges(nl *elements.NodeList) {
 			continue
 		}
 
-		edge.To = newTos
-		newEdges = append(newEdges, edge)
+		newEdges = append(newEdges, &sbom.Edge{Type: edge.Type, From: edge.From, To: newTos})
 	}
 
 	nl.Edges = newEdges
@@ -69,3 +76,106 @@ func reconnectOrphanNodes(nl *elements.NodeList) {
 		}
 	}
 }
+
+// cleanRootElements removes from the root elements list any IDs that don't
+// point to a node in the NodeList.
+func cleanRootElements(nl *elements.NodeList) {
+	idDict := map[string]struct{}{}
+	for i := range nl.Nodes {
+		idDict[nl.Nodes[i].Id] = struct{}{}
+	}
+
+	newRoots := []string{}
+	for _, id := range nl.RootElements {
+		if _, ok := idDict[id]; ok && !slices.Contains(newRoots, id) {
+			newRoots = append(newRoots, id)
+		}
+	}
+	nl.RootElements = newRoots
+}
+
+// shallowNodeListCopy returns a new NodeList with copies of the slices of
+// nl. The node and edge protos are shared, so functions can change the
+// structure of the copy but must not modify its elements.
+func shallowNodeListCopy(nl *sbom.NodeList) *elements.NodeList {
+	return &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        slices.Clone(nl.GetNodes()),
+			Edges:        slices.Clone(nl.GetEdges()),
+			RootElements: slices.Clone(nl.GetRootElements()),
+		},
+	}
+}
+
+// documentWithNodeList returns a new Document with the metadata of doc and
+// a different NodeList.
+func documentWithNodeList(doc *sbom.Document, nl *sbom.NodeList) *elements.Document {
+	return &elements.Document{
+		Document: &sbom.Document{Metadata: doc.GetMetadata(), NodeList: nl},
+	}
+}
+
+// nodeListFromVal returns the protobom NodeList wrapped in a Document or
+// NodeList value. Any other type returns an error.
+func nodeListFromVal(val ref.Val) (*sbom.NodeList, error) {
+	switch v := val.Value().(type) {
+	case *sbom.Document:
+		if v.GetNodeList() == nil {
+			return &sbom.NodeList{}, nil
+		}
+		return v.GetNodeList(), nil
+	case *sbom.NodeList:
+		if v == nil {
+			return &sbom.NodeList{}, nil
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unable to get a nodelist from type %T", val.Value())
+	}
+}
+
+// filterNodeList returns a new NodeList with copies of the nodes where keep
+// returns true. Only the edges among the selected nodes are kept and nodes
+// left without incoming edges become root elements.
+func filterNodeList(nl *sbom.NodeList, keep func(*sbom.Node) bool) *elements.NodeList {
+	ret := &elements.NodeList{
+		NodeList: &sbom.NodeList{
+			Nodes:        []*sbom.Node{},
+			Edges:        []*sbom.Edge{},
+			RootElements: []string{},
+		},
+	}
+
+	for _, n := range nl.GetNodes() {
+		if keep(n) {
+			ret.Nodes = append(ret.Nodes, n.Copy())
+		}
+	}
+
+	for _, e := range nl.GetEdges() {
+		ret.Edges = append(ret.Edges, e.Copy())
+	}
+
+	cleanEdges(ret)
+	reconnectOrphanNodes(ret)
+
+	return ret
+}
+
+// stringsFromVal reads a CEL list of strings
+func stringsFromVal(val ref.Val) ([]string, error) {
+	list, ok := val.(traits.Lister)
+	if !ok {
+		return nil, fmt.Errorf("expected a list of strings, not %T", val.Value())
+	}
+
+	ret := []string{}
+	for it := list.Iterator(); it.HasNext() == types.True; {
+		s, ok := it.Next().Value().(string)
+		if !ok {
+			return nil, errors.New("expected a list of strings")
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
@@ -0,0 +1,776 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Versioning schemes supported by the version comparison functions
+const (
+	VersionSchemeSemver = "semver"
+	VersionSchemeGolang = "golang"
+	VersionSchemePEP440 = "pep440"
+	VersionSchemeDebian = "debian"
+	VersionSchemeRPM    = "rpm"
+	VersionSchemeMaven  = "maven"
+)
+
+// versionCompareFunc compares two versions returning -1, 0 or 1
+type versionCompareFunc func(a, b string) (int, error)
+
+// versionSchemes maps the supported schemes to their comparison functions
+var versionSchemes = map[string]versionCompareFunc{
+	VersionSchemeSemver: compareSemver,
+	VersionSchemeGolang: compareSemver,
+	VersionSchemePEP440: comparePEP440,
+	VersionSchemeDebian: compareDebian,
+	VersionSchemeRPM:    compareRPM,
+	VersionSchemeMaven:  compareMaven,
+}
+
+// versionSchemeAliases maps purl and vers types to a versioning scheme
+var versionSchemeAliases = map[string]string{
+	"npm":    VersionSchemeSemver,
+	"cargo":  VersionSchemeSemver,
+	"nuget":  VersionSchemeSemver,
+	"go":     VersionSchemeGolang,
+	"pypi":   VersionSchemePEP440,
+	"python": VersionSchemePEP440,
+	"deb":    VersionSchemeDebian,
+	"rpm":    VersionSchemeRPM,
+	"maven":  VersionSchemeMaven,
+}
+
+// versionComparator returns the comparison function for a scheme name. The
+// name can be a scheme or a purl type.
+func versionComparator(scheme string) (versionCompareFunc, error) {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	if alias, ok := versionSchemeAliases[scheme]; ok {
+		scheme = alias
+	}
+	f, ok := versionSchemes[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported version scheme %q", scheme)
+	}
+	return f, nil
+}
+
+// CompareVersions compares two versions using the ordering rules of scheme.
+// It returns -1 if a sorts before b, 1 if it sorts after and 0 if they are
+// equivalent.
+func CompareVersions(a, b, scheme string) (int, error) {
+	f, err := versionComparator(scheme)
+	if err != nil {
+		return 0, err
+	}
+	return f(a, b)
+}
+
+// cmpInt returns the result of comparing two ints as -1, 0 or 1
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareNumeric compares two strings of digits of arbitrary length
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if c := cmpInt(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// isDigits returns true if the string is non empty and made only of digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// semverRegex parses semantic versions. To support the versions found in the
+// wild, the minor and patch components are optional and a leading v is
+// allowed. Go module versions (including pseudo-versions) are semver too.
+var semverRegex = regexp.MustCompile(
+	`^[vV]?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`,
+)
+
+// parseSemver returns the release components and prerelease identifiers
+func parseSemver(v string) ([3]string, []string, error) {
+	m := semverRegex.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return [3]string{}, nil, fmt.Errorf("invalid semantic version %q", v)
+	}
+	release := [3]string{m[1], m[2], m[3]}
+	for i := range release {
+		if release[i] == "" {
+			release[i] = "0"
+		}
+	}
+	var pre []string
+	if m[4] != "" {
+		pre = strings.Split(m[4], ".")
+	}
+	return release, pre, nil
+}
+
+// compareSemver compares two versions following the semver 2.0 precedence
+// rules. Build metadata is ignored.
+func compareSemver(a, b string) (int, error) {
+	relA, preA, err := parseSemver(a)
+	if err != nil {
+		return 0, err
+	}
+	relB, preB, err := parseSemver(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range relA {
+		if c := compareNumeric(relA[i], relB[i]); c != 0 {
+			return c, nil
+		}
+	}
+
+	// A version without prerelease sorts after one with it
+	switch {
+	case len(preA) == 0 && len(preB) == 0:
+		return 0, nil
+	case len(preA) == 0:
+		return 1, nil
+	case len(preB) == 0:
+		return -1, nil
+	}
+
+	for i := 0; i < len(preA) && i < len(preB); i++ {
+		numA, numB := isDigits(preA[i]), isDigits(preB[i])
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(preA[i], preB[i])
+		case numA:
+			c = -1
+		case numB:
+			c = 1
+		default:
+			c = strings.Compare(preA[i], preB[i])
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return cmpInt(len(preA), len(preB)), nil
+}
+
+// pep440Regex is the version parsing regular expression from PEP 440
+var pep440Regex = regexp.MustCompile(
+	`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+		`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` +
+		`(?:(?:-(\d+))|(?:[-_.]?(post|rev|r)[-_.]?(\d+)?))?` +
+		`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+		`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`,
+)
+
+// pep440Version is a parsed PEP 440 version
+type pep440Version struct {
+	epoch   *big.Int
+	release []*big.Int
+	pre     string // "a", "b" or "rc"
+	preN    *big.Int
+	post    *big.Int
+	dev     *big.Int
+	local   []string
+}
+
+// parseBig parses a string of digits, an empty string is zero
+func parseBig(s string) *big.Int {
+	n := new(big.Int)
+	if s != "" {
+		n.SetString(s, 10)
+	}
+	return n
+}
+
+// parsePEP440 parses a python package version
+func parsePEP440(v string) (*pep440Version, error) {
+	m := pep440Regex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(v)))
+	if m == nil {
+		return nil, fmt.Errorf("invalid PEP 440 version %q", v)
+	}
+
+	ret := &pep440Version{epoch: parseBig(m[1])}
+	for _, s := range strings.Split(m[2], ".") {
+		ret.release = append(ret.release, parseBig(s))
+	}
+
+	switch m[3] {
+	case "":
+	case "a", "alpha":
+		ret.pre = "a"
+	case "b", "beta":
+		ret.pre = "b"
+	default:
+		ret.pre = "rc"
+	}
+	if ret.pre != "" {
+		ret.preN = parseBig(m[4])
+	}
+
+	switch {
+	case m[5] != "":
+		ret.post = parseBig(m[5])
+	case m[6] != "":
+		ret.post = parseBig(m[7])
+	}
+
+	if m[8] != "" {
+		ret.dev = parseBig(m[9])
+	}
+
+	if m[10] != "" {
+		ret.local = strings.FieldsFunc(m[10], func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+	}
+	return ret, nil
+}
+
+// preRank orders the prerelease segment. A dev release without a
+// prerelease or postrelease sorts before every prerelease.
+func (v *pep440Version) preRank() int {
+	switch {
+	case v.pre == "" && v.post == nil && v.dev != nil:
+		return -1
+	case v.pre == "a":
+		return 0
+	case v.pre == "b":
+		return 1
+	case v.pre == "rc":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// cmpOptionalBig compares two optional numbers, missing sorts as indicated
+// by the missing parameter.
+func cmpOptionalBig(a, b *big.Int, missing int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return missing
+	case b == nil:
+		return -missing
+	default:
+		return a.Cmp(b)
+	}
+}
+
+// comparePEP440 compares two python package versions as defined in PEP 440
+func comparePEP440(a, b string) (int, error) {
+	va, err := parsePEP440(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parsePEP440(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if c := va.epoch.Cmp(vb.epoch); c != 0 {
+		return c, nil
+	}
+
+	// Trailing zeros in the release segment are not significant
+	zero := new(big.Int)
+	for i := 0; i < len(va.release) || i < len(vb.release); i++ {
+		ra, rb := zero, zero
+		if i < len(va.release) {
+			ra = va.release[i]
+		}
+		if i < len(vb.release) {
+			rb = vb.release[i]
+		}
+		if c := ra.Cmp(rb); c != 0 {
+			return c, nil
+		}
+	}
+
+	if c := cmpInt(va.preRank(), vb.preRank()); c != 0 {
+		return c, nil
+	}
+	if c := cmpOptionalBig(va.preN, vb.preN, 0); c != 0 {
+		return c, nil
+	}
+	// No post release sorts first
+	if c := cmpOptionalBig(va.post, vb.post, -1); c != 0 {
+		return c, nil
+	}
+	// No dev release sorts last
+	if c := cmpOptionalBig(va.dev, vb.dev, 1); c != 0 {
+		return c, nil
+	}
+
+	// Local versions sort after the public version, numeric segments sort
+	// after alphanumeric ones.
+	for i := 0; i < len(va.local) && i < len(vb.local); i++ {
+		numA, numB := isDigits(va.local[i]), isDigits(vb.local[i])
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(va.local[i], vb.local[i])
+		case numA:
+			c = 1
+		case numB:
+			c = -1
+		default:
+			c = strings.Compare(va.local[i], vb.local[i])
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return cmpInt(len(va.local), len(vb.local)), nil
+}
+
+// splitEpoch splits the epoch from a debian or rpm version string
+func splitEpoch(v string) (epoch, rest string, err error) {
+	epoch, rest, ok := strings.Cut(v, ":")
+	if !ok {
+		return "0", v, nil
+	}
+	if !isDigits(epoch) {
+		return "", "", fmt.Errorf("invalid epoch in version %q", v)
+	}
+	return epoch, rest, nil
+}
+
+// debianOrder returns the sort weight of a character in a debian version
+func debianOrder(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	case c == '~':
+		return -1
+	case c == 0:
+		return 0
+	default:
+		return int(c) + 256
+	}
+}
+
+// debianVerRevCmp compares the upstream or revision part of a debian
+// version. This is a port of verrevcmp() from dpkg.
+func debianVerRevCmp(a, b string) int {
+	i, j := 0, 0
+	at := func(s string, i int) byte {
+		if i < len(s) {
+			return s[i]
+		}
+		return 0
+	}
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+
+	for i < len(a) || j < len(b) {
+		firstDiff := 0
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := debianOrder(at(a, i)), debianOrder(at(b, j))
+			if ac != bc {
+				return cmpInt(ac, bc)
+			}
+			i++
+			j++
+		}
+		for at(a, i) == '0' {
+			i++
+		}
+		for at(b, j) == '0' {
+			j++
+		}
+		for isDigit(at(a, i)) && isDigit(at(b, j)) {
+			if firstDiff == 0 {
+				firstDiff = cmpInt(int(a[i]), int(b[j]))
+			}
+			i++
+			j++
+		}
+		if isDigit(at(a, i)) {
+			return 1
+		}
+		if isDigit(at(b, j)) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
+
+// compareDebian compares two debian package versions
+func compareDebian(a, b string) (int, error) {
+	epochA, restA, err := splitEpoch(strings.TrimSpace(a))
+	if err != nil {
+		return 0, err
+	}
+	epochB, restB, err := splitEpoch(strings.TrimSpace(b))
+	if err != nil {
+		return 0, err
+	}
+	if c := compareNumeric(epochA, epochB); c != 0 {
+		return c, nil
+	}
+
+	upA, revA := restA, ""
+	if i := strings.LastIndex(restA, "-"); i != -1 {
+		upA, revA = restA[:i], restA[i+1:]
+	}
+	upB, revB := restB, ""
+	if i := strings.LastIndex(restB, "-"); i != -1 {
+		upB, revB = restB[:i], restB[i+1:]
+	}
+
+	if c := debianVerRevCmp(upA, upB); c != 0 {
+		return c, nil
+	}
+	return debianVerRevCmp(revA, revB), nil
+}
+
+// rpmVerCmp compares two rpm version or release strings. This is a port
+// of rpmvercmp() from librpm.
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	isAlnum := func(c byte) bool {
+		return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+
+	for a != "" || b != "" {
+		for a != "" && !isAlnum(a[0]) && a[0] != '~' && a[0] != '^' {
+			a = a[1:]
+		}
+		for b != "" && !isAlnum(b[0]) && b[0] != '~' && b[0] != '^' {
+			b = b[1:]
+		}
+
+		// Tilde sorts before everything, even the end of the string
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		// Caret sorts after the end of the string but before anything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case !strings.HasPrefix(a, "^"):
+				return 1
+			case !strings.HasPrefix(b, "^"):
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigit(a[0])
+		segment := func(s string) (string, string) {
+			i := 0
+			for i < len(s) && isAlnum(s[i]) && isDigit(s[i]) == numeric {
+				i++
+			}
+			return s[:i], s[i:]
+		}
+		var segA, segB string
+		segA, a = segment(a)
+		segB, b = segment(b)
+
+		// Numeric segments are newer than alphabetic ones
+		if segB == "" {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		var c int
+		if numeric {
+			c = compareNumeric(segA, segB)
+		} else {
+			c = strings.Compare(segA, segB)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareRPM compares two [epoch:]version[-release] rpm versions
+func compareRPM(a, b string) (int, error) {
+	epochA, restA, err := splitEpoch(strings.TrimSpace(a))
+	if err != nil {
+		return 0, err
+	}
+	epochB, restB, err := splitEpoch(strings.TrimSpace(b))
+	if err != nil {
+		return 0, err
+	}
+	if c := compareNumeric(epochA, epochB); c != 0 {
+		return c, nil
+	}
+
+	verA, relA, _ := strings.Cut(restA, "-")
+	verB, relB, _ := strings.Cut(restB, "-")
+	if c := rpmVerCmp(verA, verB); c != 0 {
+		return c, nil
+	}
+	// A missing release matches any release
+	if relA == "" || relB == "" {
+		return 0, nil
+	}
+	return rpmVerCmp(relA, relB), nil
+}
+
+// mavenQualifiers lists the well known maven qualifiers in order. Unknown
+// qualifiers sort after these, in lexical order.
+var mavenQualifiers = map[string]int{
+	"alpha":     0,
+	"beta":      1,
+	"milestone": 2,
+	"rc":        3,
+	"snapshot":  4,
+	"":          5,
+	"sp":        6,
+}
+
+// mavenQualifierAliases maps the qualifier shorthands and synonyms
+var mavenQualifierAliases = map[string]string{
+	"a":       "alpha",
+	"b":       "beta",
+	"m":       "milestone",
+	"cr":      "rc",
+	"ga":      "",
+	"final":   "",
+	"release": "",
+}
+
+// mavenItem is a component of a maven version: a number, a qualifier or a
+// sublist of items.
+type mavenItem struct {
+	numeric bool
+	value   string
+
+	// isList marks sublists, started by hyphens and transitions between
+	// digits and letters
+	isList bool
+	items  []*mavenItem
+}
+
+// newMavenItem parses a number or qualifier
+func newMavenItem(s string) *mavenItem {
+	if isDigits(s) {
+		return &mavenItem{numeric: true, value: strings.TrimLeft(s, "0")}
+	}
+	if alias, ok := mavenQualifierAliases[s]; ok {
+		s = alias
+	}
+	return &mavenItem{value: s}
+}
+
+// parseMaven parses a maven version into a list of items following the
+// rules of maven's ComparableVersion: items are separated by dots, while
+// hyphens and transitions between digits and letters start a sublist. Null
+// items (zeros and release qualifiers) at the end of each list are dropped.
+func parseMaven(v string) *mavenItem {
+	v = strings.ToLower(strings.TrimSpace(v))
+	root := &mavenItem{isList: true}
+	list := root
+	start := 0
+	add := func(end int) {
+		if end == start {
+			list.items = append(list.items, &mavenItem{numeric: true})
+			return
+		}
+		list.items = append(list.items, newMavenItem(v[start:end]))
+	}
+	sublist := func() {
+		sub := &mavenItem{isList: true}
+		list.items = append(list.items, sub)
+		list = sub
+	}
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+
+	for i := 0; i < len(v); i++ {
+		switch {
+		case v[i] == '.':
+			add(i)
+			start = i + 1
+		case v[i] == '-':
+			add(i)
+			start = i + 1
+			sublist()
+		case i > start && isDigit(v[i]) != isDigit(v[i-1]):
+			add(i)
+			start = i
+			sublist()
+		}
+	}
+	if start < len(v) {
+		add(len(v))
+	}
+
+	root.normalize()
+	return root
+}
+
+// normalize drops the null items at the end of a list and its sublists
+func (i *mavenItem) normalize() {
+	for n := len(i.items) - 1; n >= 0; n-- {
+		item := i.items[n]
+		if item.isList {
+			item.normalize()
+		}
+		if item.isNull() {
+			i.items = append(i.items[:n], i.items[n+1:]...)
+		} else if !item.isList {
+			break
+		}
+	}
+}
+
+// isNull returns true if the item is equivalent to a missing item
+func (i *mavenItem) isNull() bool {
+	if i.isList {
+		return len(i.items) == 0
+	}
+	return i.value == ""
+}
+
+// compareMavenQualifiers compares two maven qualifiers. Unknown qualifiers
+// sort after the known ones, in lexical order.
+func compareMavenQualifiers(a, b string) int {
+	ra, knownA := mavenQualifiers[a]
+	rb, knownB := mavenQualifiers[b]
+	switch {
+	case knownA && knownB:
+		return cmpInt(ra, rb)
+	case knownA:
+		return -1
+	case knownB:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compare compares two maven version items. A nil item is a missing one.
+func (i *mavenItem) compare(o *mavenItem) int {
+	switch {
+	case i.isList:
+		if o == nil {
+			if len(i.items) == 0 {
+				return 0
+			}
+			return i.items[0].compare(nil)
+		}
+		if !o.isList {
+			// Sublists sort before numbers and after qualifiers
+			if o.numeric {
+				return -1
+			}
+			return 1
+		}
+		for n := 0; n < len(i.items) || n < len(o.items); n++ {
+			var a, b *mavenItem
+			if n < len(i.items) {
+				a = i.items[n]
+			}
+			if n < len(o.items) {
+				b = o.items[n]
+			}
+			c := 0
+			switch {
+			case a == nil && b != nil:
+				c = -b.compare(nil)
+			case a != nil:
+				c = a.compare(b)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	case i.numeric:
+		switch {
+		case o == nil:
+			return compareNumeric(i.value, "")
+		case o.numeric:
+			return compareNumeric(i.value, o.value)
+		default:
+			// Numbers sort after qualifiers and sublists
+			return 1
+		}
+	default:
+		switch {
+		case o == nil:
+			return compareMavenQualifiers(i.value, "")
+		case o.numeric, o.isList:
+			return -1
+		default:
+			return compareMavenQualifiers(i.value, o.value)
+		}
+	}
+}
+
+// compareMaven compares two maven versions
+func compareMaven(a, b string) (int, error) {
+	if strings.TrimSpace(a) == "" || strings.TrimSpace(b) == "" {
+		return 0, fmt.Errorf("invalid maven version comparison %q vs %q", a, b)
+	}
+	return parseMaven(a).compare(parseMaven(b)), nil
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package functions
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/elements"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		scheme   string
+		a        string
+		b        string
+		expected int
+		mustErr  bool
+	}{
+		// semver
+		{"semver", "1.10.0", "1.9.0", 1, false},
+		{"semver", "1.0.0", "1.0.0", 0, false},
+		{"semver", "v1.2", "1.2.0", 0, false},
+		{"semver", "1.0.0-alpha", "1.0.0", -1, false},
+		{"semver", "1.0.0-alpha.1", "1.0.0-alpha.beta", -1, false},
+		{"semver", "1.0.0-beta.11", "1.0.0-beta.2", 1, false},
+		{"semver", "1.0.0-rc.1", "1.0.0-rc.1.1", -1, false},
+		{"semver", "1.0.0+build.1", "1.0.0+build.2", 0, false},
+		{"npm", "10.0.0", "9.99.99", 1, false},
+		{"semver", "not-a-version", "1.0.0", 0, true},
+
+		// Go modules, including pseudo-versions
+		{"golang", "v0.0.0-20210622060536-734e95fb86be", "v0.0.0-20230101000000-abcdefabcdef", -1, false},
+		{"golang", "v1.2.4-0.20230101000000-abcdefabcdef", "v1.2.3", 1, false},
+		{"golang", "v1.2.4-0.20230101000000-abcdefabcdef", "v1.2.4", -1, false},
+		{"golang", "v24.0.0+incompatible", "v24.0.0", 0, false},
+
+		// PEP 440
+		{"pep440", "1.0.dev1", "1.0a1", -1, false},
+		{"pep440", "1.0a1", "1.0b1", -1, false},
+		{"pep440", "1.0rc1", "1.0", -1, false},
+		{"pep440", "1.0", "1.0.post1", -1, false},
+		{"pep440", "1.0.post1.dev1", "1.0.post1", -1, false},
+		{"pep440", "1.0", "1.0.0", 0, false},
+		{"pep440", "1.0", "1.0+local.1", -1, false},
+		{"pep440", "1!0.1", "2.0", 1, false},
+		{"pypi", "2.10", "2.9", 1, false},
+		{"pep440", "1.0-1", "1.0.post1", 0, false},
+		{"pep440", "not a version", "1.0", 0, true},
+
+		// Debian
+		{"debian", "1.0~rc1", "1.0", -1, false},
+		{"debian", "1:1.0", "2.0", 1, false},
+		{"debian", "1.0-1", "1.0-2", -1, false},
+		{"debian", "1.0-1ubuntu1", "1.0-1", 1, false},
+		{"deb", "2.30-8+deb11u1", "2.30-8", 1, false},
+		{"debian", "1.0a", "1.0", 1, false},
+		{"debian", "1.0.10", "1.0.9", 1, false},
+		{"debian", "x:1.0", "1.0", 0, true},
+
+		// RPM
+		{"rpm", "1.0", "1.0", 0, false},
+		{"rpm", "1.0~rc1", "1.0", -1, false},
+		{"rpm", "1.0^git1", "1.0", 1, false},
+		{"rpm", "1.0^git1", "1.0.1", -1, false},
+		{"rpm", "1.0-1.el8", "1.0-2.el8", -1, false},
+		{"rpm", "1:1.0", "2.0", 1, false},
+		{"rpm", "1.0a", "1.0.1", -1, false},
+		{"rpm", "2.0-1", "2.0", 0, false},
+
+		// Maven
+		{"maven", "1.0", "1.0.0", 0, false},
+		{"maven", "1.0-alpha-1", "1.0", -1, false},
+		{"maven", "1.0-beta", "1.0-rc1", -1, false},
+		{"maven", "1.0-SNAPSHOT", "1.0", -1, false},
+		{"maven", "1.0", "1.0-sp1", -1, false},
+		{"maven", "1.0.Final", "1.0", 0, false},
+		{"maven", "1.10", "1.9", 1, false},
+		{"maven", "1.0-foo", "1.0-sp", 1, false},
+		{"maven", "1.0-alpha", "1-alpha", 0, false},
+		{"maven", "1.0.0-rc-1", "1-rc-1", 0, false},
+		{"maven", "1-ga", "1.0.0", 0, false},
+		{"maven", "1.0-final-0", "1", 0, false},
+		{"maven", "1-1", "1.1", -1, false},
+		{"maven", "1.0-1", "1.0.1", -1, false},
+		{"maven", "1.0-alpha", "1.0.1-alpha", -1, false},
+		{"maven", "1-sp", "1.0", 1, false},
+
+		{"unknown", "1.0", "1.0", 0, true},
+		{"apk", "1.2.3_rc1", "1.2.3", 0, true},
+		{"alpm", "1.0-1", "1.0-2", 0, true},
+	} {
+		t.Run(tc.scheme+"/"+tc.a+"/"+tc.b, func(t *testing.T) {
+			res, err := CompareVersions(tc.a, tc.b, tc.scheme)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+
+			// The comparison must be symmetric
+			res, err = CompareVersions(tc.b, tc.a, tc.scheme)
+			require.NoError(t, err)
+			require.Equal(t, -tc.expected, res)
+		})
+	}
+}
+
+func TestVersRange(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		vers     string
+		version  string
+		expected bool
+		mustErr  bool
+	}{
+		{"any", "vers:semver/*", "1.0.0", true, false},
+		{"equal", "vers:semver/1.0.0", "1.0.0", true, false},
+		{"not-equal", "vers:semver/1.0.0", "1.0.1", false, false},
+		{"less", "vers:semver/<2.0.0", "1.9.9", true, false},
+		{"less-edge", "vers:semver/<2.0.0", "2.0.0", false, false},
+		{"less-equal-edge", "vers:semver/<=2.0.0", "2.0.0", true, false},
+		{"greater", "vers:semver/>1.0.0", "1.0.1", true, false},
+		{"interval", "vers:semver/>=1.2.0|<1.3.0", "1.2.5", true, false},
+		{"interval-below", "vers:semver/>=1.2.0|<1.3.0", "1.1.0", false, false},
+		{"interval-above", "vers:semver/>=1.2.0|<1.3.0", "1.3.0", false, false},
+		{"intervals", "vers:semver/<1.0.0|>=2.0.0|<2.1.0|>=3.0.0", "2.0.5", true, false},
+		{"intervals-gap", "vers:semver/<1.0.0|>=2.0.0|<2.1.0|>=3.0.0", "2.5.0", false, false},
+		{"intervals-last", "vers:semver/<1.0.0|>=2.0.0|<2.1.0|>=3.0.0", "3.1.0", true, false},
+		{"excluded", "vers:semver/>=1.0.0|!=1.0.5|<2.0.0", "1.0.5", false, false},
+		{"unordered", "vers:semver/<1.3.0|>=1.2.0", "1.2.5", true, false},
+		{"spaces", "vers:semver/ >= 1.2.0 | < 1.3.0 ", "1.2.5", true, false},
+		{"escaped", "vers:semver/1.0.0%2Bbuild", "1.0.0", true, false},
+		{"no-prefix", ">=1.0.0|<2.0.0", "1.5.0", true, false},
+		{"empty", "vers:semver/", "1.0.0", false, true},
+		{"no-scheme", "vers:/1.0.0", "1.0.0", false, true},
+		{"empty-constraint", "vers:semver/>=1.0.0||<2.0.0", "1.0.0", false, true},
+		{"no-version", "vers:semver/>=", "1.0.0", false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := parseVersRange(tc.vers)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			res, err := r.Contains(tc.version, compareSemver)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestNodeVersionInRange(t *testing.T) {
+	debNode := purlNode("curl", "pkg:deb/debian/curl@7.74.0-1.3+deb11u7")
+	goNode := purlNode("cosign", "pkg:golang/github.com/sigstore/cosign/v2@v2.0.0")
+	goNode.Version = "v2.0.0"
+
+	for _, tc := range []struct {
+		name     string
+		node     *sbom.Node
+		vers     string
+		expected bool
+		mustErr  bool
+	}{
+		{"purl-scheme", debNode, "<7.74.0-1.3+deb11u8", true, false},
+		{"purl-scheme-out", debNode, "<7.74.0-1.3+deb11u7", false, false},
+		{"node-version", goNode, ">=v2.0.0|<v2.0.1", true, false},
+		{"explicit-scheme", goNode, "vers:golang/<v2.0.0-rc.1", false, false},
+		{"no-version", &sbom.Node{Id: "nover"}, "*", false, false},
+		{"bad-scheme", goNode, "vers:nope/1.0", false, true},
+		{"unsupported-purl-scheme", purlNode("busybox", "pkg:apk/alpine/busybox@1.36.1_rc1"), "<1.36.1", false, false},
+		{"bad-version", goNode, "vers:semver/<abc", false, true},
+		{"unparsable-node-version", purlNode("action", "pkg:githubactions/actions/checkout@8ade135a41bc03ea155e62e844d188df1ea18608"), ">=1.0.0", false, false},
+		{"unparsable-node-version-scheme", purlNode("tool", "pkg:generic/tool@latest"), "vers:semver/>=1.0.0", false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NodeVersionInRange(&elements.Node{Node: tc.node}, types.String(tc.vers))
+			if tc.mustErr {
+				require.True(t, types.IsError(res), res)
+				return
+			}
+			require.False(t, types.IsError(res), res)
+			require.Equal(t, types.Bool(tc.expected), res)
+		})
+	}
+}
@@ -4,8 +4,12 @@
 package library
 
 import (
+	"fmt"
+
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/operators"
 	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
 
 	"github.com/protobom/cel/pkg/elements"
 	"github.com/protobom/cel/pkg/functions"
@@ -54,7 +58,17 @@ func (p *Protobom) Functions() []cel.EnvOption {
 				[]*cel.Type{elements.NodeListType, elements.NodeListType},
 				elements.NodeListType,
 				cel.BinaryBinding(functions.Addition),
-				// cel.OverloadOperandTrait(traits.AdderType),
+			),
+		),
+
+		// The + operator on NodeLists is equivalent to add(). It is handled
+		// by the standard library which dispatches it to the adder trait.
+		cel.Function(
+			operators.Add,
+			cel.Overload(
+				"add_nodelist_nodelist",
+				[]*cel.Type{elements.NodeListType, elements.NodeListType},
+				elements.NodeListType,
 			),
 		),
 
@@ -106,17 +120,124 @@ func (p *Protobom) Functions() []cel.EnvOption {
 			),
 		),
 
+		// get_nodes_by_purl returns a NodeList including all nodes whose
+		// package URL matches a purl, version-less purl or glob pattern.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"get_nodes_by_purl",
+			cel.MemberOverload(
+				"sbom_nodesbypurl_binding", []*cel.Type{elements.DocumentType, cel.StringType}, elements.NodeListType,
+				cel.BinaryBinding(functions.NodesByPurl),
+			),
+			cel.MemberOverload(
+				"nodelist_nodesbypurl_binding", []*cel.Type{elements.NodeListType, cel.StringType}, elements.NodeListType,
+				cel.BinaryBinding(functions.NodesByPurl),
+			),
+		),
+
+		// purl returns the components of a node's package URL as a map
+		cel.Function(
+			"purl",
+			cel.MemberOverload(
+				"node_purl_binding", []*cel.Type{elements.NodeType}, cel.MapType(cel.StringType, cel.DynType),
+				cel.UnaryBinding(functions.NodePurl),
+			),
+		),
+
+		// compare_versions compares two versions using the ordering rules of
+		// a versioning scheme. Returns -1, 0 or 1.
+		cel.Function(
+			"compare_versions",
+			cel.MemberOverload(
+				"protobom_compareversions_binding",
+				[]*cel.Type{elements.ProtobomType, cel.StringType, cel.StringType, cel.StringType}, cel.IntType,
+				cel.FunctionBinding(functions.CompareVersionsBinding),
+			),
+		),
+
+		// version_in_range checks if the version of a node is in a vers range
+		cel.Function(
+			"version_in_range",
+			cel.MemberOverload(
+				"node_versioninrange_binding", []*cel.Type{elements.NodeType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(functions.NodeVersionInRange),
+			),
+		),
+
+		// get_nodes_by_license returns a NodeList with the nodes that have a
+		// license identifier matching a glob pattern.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"get_nodes_by_license",
+			cel.MemberOverload(
+				"sbom_nodesbylicense_binding", []*cel.Type{elements.DocumentType, cel.StringType}, elements.NodeListType,
+				cel.BinaryBinding(functions.NodesByLicense),
+			),
+			cel.MemberOverload(
+				"nodelist_nodesbylicense_binding", []*cel.Type{elements.NodeListType, cel.StringType}, elements.NodeListType,
+				cel.BinaryBinding(functions.NodesByLicense),
+			),
+		),
+
+		// license_ids returns the license identifiers of a node
+		cel.Function(
+			"license_ids",
+			cel.MemberOverload(
+				"node_licenseids_binding", []*cel.Type{elements.NodeType}, cel.ListType(cel.StringType),
+				cel.UnaryBinding(functions.NodeLicenseIDs),
+			),
+		),
+
+		// license_satisfies checks if the node license can be fulfilled with
+		// a list of licenses
+		cel.Function(
+			"license_satisfies",
+			cel.MemberOverload(
+				"node_licensesatisfies_binding", []*cel.Type{elements.NodeType, cel.ListType(cel.StringType)}, cel.BoolType,
+				cel.BinaryBinding(functions.NodeLicenseSatisfies),
+			),
+		),
+
+		// is_copyleft checks if the node license requires a copyleft license
+		cel.Function(
+			"is_copyleft",
+			cel.MemberOverload(
+				"node_iscopyleft_binding", []*cel.Type{elements.NodeType}, cel.BoolType,
+				cel.UnaryBinding(functions.NodeIsCopyleft),
+			),
+		),
+
+		// license_info returns the data of a license in the SPDX license list
+		cel.Function(
+			"license_info",
+			cel.MemberOverload(
+				"protobom_licenseinfo_binding",
+				[]*cel.Type{elements.ProtobomType, cel.StringType}, cel.MapType(cel.StringType, cel.DynType),
+				cel.BinaryBinding(functions.LicenseInfo),
+			),
+		),
+
+		// is_valid_license checks a license expression against the SPDX list
+		cel.Function(
+			"is_valid_license",
+			cel.MemberOverload(
+				"protobom_isvalidlicense_binding",
+				[]*cel.Type{elements.ProtobomType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(functions.IsValidLicense),
+			),
+		),
+
 		// NodesByPurlType returns a NodeList including all nodes that have a
 		// package URL of a certain type.
 		// Overloaded in: Document and NodeList.
 		cel.Function(
 			"get_root_nodes",
 			cel.MemberOverload(
-				"doc_rootnodes_binding", []*cel.Type{elements.DocumentType}, cel.ListType(cel.DynType),
+				"doc_rootnodes_binding", []*cel.Type{elements.DocumentType}, cel.ListType(elements.NodeType),
 				cel.UnaryBinding(functions.RootNodes),
 			),
 			cel.MemberOverload(
-				"nodelist_rootnodes_binding", []*cel.Type{elements.NodeListType}, cel.ListType(cel.DynType),
+				"nodelist_rootnodes_binding", []*cel.Type{elements.NodeListType}, cel.ListType(elements.NodeType),
 				cel.UnaryBinding(functions.RootNodes),
 			),
 		),
@@ -125,7 +246,7 @@ func (p *Protobom) Functions() []cel.EnvOption {
 		cel.Function(
 			"get_suppliers",
 			cel.MemberOverload(
-				"node_getsuppliers_binding", []*cel.Type{elements.NodeType}, types.ListType,
+				"node_getsuppliers_binding", []*cel.Type{elements.NodeType}, cel.ListType(elements.PersonType),
 				cel.UnaryBinding(functions.NodeGetSuppliers),
 			),
 		),
@@ -134,16 +255,68 @@ func (p *Protobom) Functions() []cel.EnvOption {
 		cel.Function(
 			"get_originators",
 			cel.MemberOverload(
-				"node_getoriginators_binding", []*cel.Type{elements.NodeType}, types.ListType,
+				"node_getoriginators_binding", []*cel.Type{elements.NodeType}, cel.ListType(elements.PersonType),
 				cel.UnaryBinding(functions.NodeGetOriginators),
 			),
 		),
 
+		// with_property returns a copy of the node with a property set
+		cel.Function(
+			"with_property",
+			cel.MemberOverload(
+				"node_withproperty_binding", []*cel.Type{elements.NodeType, cel.StringType, cel.StringType}, elements.NodeType,
+				cel.FunctionBinding(functions.NodeWithProperty),
+			),
+		),
+
+		// with_supplier returns a copy of the node with a new supplier
+		cel.Function(
+			"with_supplier",
+			cel.MemberOverload(
+				"node_withsupplier_binding", []*cel.Type{elements.NodeType, cel.StringType, cel.StringType}, elements.NodeType,
+				cel.FunctionBinding(functions.NodeWithSupplier),
+			),
+		),
+
+		// with_license_concluded returns a copy of the node with a new
+		// concluded license
+		cel.Function(
+			"with_license_concluded",
+			cel.MemberOverload(
+				"node_withlicenseconcluded_binding", []*cel.Type{elements.NodeType, cel.StringType}, elements.NodeType,
+				cel.BinaryBinding(functions.NodeWithLicenseConcluded),
+			),
+		),
+
+		// with_version returns a copy of the node with a new version
+		cel.Function(
+			"with_version",
+			cel.MemberOverload(
+				"node_withversion_binding", []*cel.Type{elements.NodeType, cel.StringType}, elements.NodeType,
+				cel.BinaryBinding(functions.NodeWithVersion),
+			),
+		),
+
+		// replace_node returns a copy of a Document or NodeList with a node
+		// replaced by another one with the same ID.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"replace_node",
+			cel.MemberOverload(
+				"sbom_replacenode_binding", []*cel.Type{elements.DocumentType, elements.NodeType}, elements.DocumentType,
+				cel.BinaryBinding(functions.ReplaceNode),
+			),
+			cel.MemberOverload(
+				"nodelist_replacenode_binding", []*cel.Type{elements.NodeListType, elements.NodeType}, elements.NodeListType,
+				cel.BinaryBinding(functions.ReplaceNode),
+			),
+		),
+
 		// Overloaded in: Document and NodeList.
 		cel.Function(
 			"get_nodes",
 			cel.MemberOverload(
-				"enodelist_get_nodes", []*cel.Type{elements.NodeListType}, types.NewListType(types.DynType),
+				"enodelist_get_nodes", []*cel.Type{elements.NodeListType}, cel.ListType(elements.NodeType),
 				cel.UnaryBinding(functions.GetNodes),
 			),
 		),
@@ -156,6 +329,67 @@ func (p *Protobom) Functions() []cel.EnvOption {
 			),
 		),
 
+		// get_edges_by_type returns the edges of one or more relationship
+		// types.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"get_edges_by_type",
+			cel.MemberOverload(
+				"sbom_get_edges_by_type", []*cel.Type{elements.DocumentType, cel.StringType}, cel.ListType(elements.EdgeType),
+				cel.BinaryBinding(functions.EdgesByType),
+			),
+			cel.MemberOverload(
+				"sbom_get_edges_by_type_list", []*cel.Type{elements.DocumentType, cel.ListType(cel.StringType)}, cel.ListType(elements.EdgeType),
+				cel.BinaryBinding(functions.EdgesByType),
+			),
+			cel.MemberOverload(
+				"nodelist_get_edges_by_type", []*cel.Type{elements.NodeListType, cel.StringType}, cel.ListType(elements.EdgeType),
+				cel.BinaryBinding(functions.EdgesByType),
+			),
+			cel.MemberOverload(
+				"nodelist_get_edges_by_type_list", []*cel.Type{elements.NodeListType, cel.ListType(cel.StringType)}, cel.ListType(elements.EdgeType),
+				cel.BinaryBinding(functions.EdgesByType),
+			),
+		),
+
+		// get_related returns the nodes directly connected to a node through
+		// edges of one or more relationship types, in both directions.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"get_related",
+			cel.MemberOverload(
+				"sbom_get_related",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.RelatedNodes),
+			),
+			cel.MemberOverload(
+				"sbom_get_related_list",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.ListType(cel.StringType)},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.RelatedNodes),
+			),
+			cel.MemberOverload(
+				"nodelist_get_related",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.RelatedNodes),
+			),
+			cel.MemberOverload(
+				"nodelist_get_related_list",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.ListType(cel.StringType)},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.RelatedNodes),
+			),
+		),
+
+		// get_nodes_at_depth, get_graph_at_depth and depth_of query the
+		// graph by the distance of the nodes to the root elements.
+		// Overloaded in: Document and NodeList.
+		cel.Function("get_nodes_at_depth", depthOverloads("get_nodes_at_depth", cel.IntType, elements.NodeListType, functions.NodesAtDepth)...),
+		cel.Function("get_graph_at_depth", depthOverloads("get_graph_at_depth", cel.IntType, elements.NodeListType, functions.GraphAtDepth)...),
+		cel.Function("depth_of", depthOverloads("depth_of", cel.StringType, cel.IntType, functions.DepthOf)...),
+
 		// GetNodeList returns a document's NodeList
 		cel.Function(
 			"get_node_list",
@@ -215,13 +449,13 @@ func (p *Protobom) Functions() []cel.EnvOption {
 			cel.MemberOverload(
 				"sbom_get_authors",
 				[]*cel.Type{elements.DocumentType},
-				types.ListType, // result
+				cel.ListType(elements.PersonType), // result
 				cel.UnaryBinding(functions.GetAuthors),
 			),
 			cel.MemberOverload(
 				"metadata_get_authors",
 				[]*cel.Type{elements.MetadataType},
-				types.ListType, // result
+				cel.ListType(elements.PersonType), // result
 				cel.UnaryBinding(functions.GetAuthors),
 			),
 		),
@@ -231,8 +465,8 @@ func (p *Protobom) Functions() []cel.EnvOption {
 			cel.MemberOverload(
 				"nodelist_nodes_by_name",
 				[]*cel.Type{elements.NodeListType, types.StringType}, // args
-				types.ListType, // result
-				cel.BinaryBinding(functions.GetNodesByName), // handler
+				cel.ListType(elements.NodeType),                      // result
+				cel.BinaryBinding(functions.GetNodesByName),          // handler
 			),
 		),
 		cel.Function(
@@ -244,8 +478,232 @@ func (p *Protobom) Functions() []cel.EnvOption {
 				cel.FunctionBinding(functions.NodeDescendants),                      // handler
 			),
 		),
+
+		// get_node_ancestors returns a NodeList with the nodes that reach a
+		// node, walking the graph backwards.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"get_node_ancestors",
+			cel.MemberOverload(
+				"sbom_node_ancestors",
+				[]*cel.Type{elements.DocumentType, types.StringType, types.IntType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.NodeAncestors),
+			),
+			cel.MemberOverload(
+				"sbom_node_ancestors_edgetype",
+				[]*cel.Type{elements.DocumentType, types.StringType, types.IntType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.NodeAncestors),
+			),
+			cel.MemberOverload(
+				"sbom_node_ancestors_edgetypes",
+				[]*cel.Type{elements.DocumentType, types.StringType, types.IntType, cel.ListType(cel.StringType)},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.NodeAncestors),
+			),
+			cel.MemberOverload(
+				"nodelist_node_ancestors",
+				[]*cel.Type{elements.NodeListType, types.StringType, types.IntType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.NodeAncestors),
+			),
+			cel.MemberOverload(
+				"nodelist_node_ancestors_edgetype",
+				[]*cel.Type{elements.NodeListType, types.StringType, types.IntType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.NodeAncestors),
+			),
+			cel.MemberOverload(
+				"nodelist_node_ancestors_edgetypes",
+				[]*cel.Type{elements.NodeListType, types.StringType, types.IntType, cel.ListType(cel.StringType)},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.NodeAncestors),
+			),
+		),
+
+		// get_paths returns all the paths between two nodes as NodeLists
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"get_paths",
+			cel.MemberOverload(
+				"sbom_get_paths",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.StringType, cel.IntType},
+				cel.ListType(elements.NodeListType),
+				cel.FunctionBinding(functions.NodePaths),
+			),
+			cel.MemberOverload(
+				"sbom_get_paths_edgetype",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.StringType, cel.IntType, cel.StringType},
+				cel.ListType(elements.NodeListType),
+				cel.FunctionBinding(functions.NodePaths),
+			),
+			cel.MemberOverload(
+				"sbom_get_paths_edgetypes",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.StringType, cel.IntType, cel.ListType(cel.StringType)},
+				cel.ListType(elements.NodeListType),
+				cel.FunctionBinding(functions.NodePaths),
+			),
+			cel.MemberOverload(
+				"nodelist_get_paths",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.StringType, cel.IntType},
+				cel.ListType(elements.NodeListType),
+				cel.FunctionBinding(functions.NodePaths),
+			),
+			cel.MemberOverload(
+				"nodelist_get_paths_edgetype",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.StringType, cel.IntType, cel.StringType},
+				cel.ListType(elements.NodeListType),
+				cel.FunctionBinding(functions.NodePaths),
+			),
+			cel.MemberOverload(
+				"nodelist_get_paths_edgetypes",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.StringType, cel.IntType, cel.ListType(cel.StringType)},
+				cel.ListType(elements.NodeListType),
+				cel.FunctionBinding(functions.NodePaths),
+			),
+		),
+
+		// get_shortest_path returns a NodeList with the shortest path
+		// between two nodes.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"get_shortest_path",
+			cel.MemberOverload(
+				"sbom_get_shortest_path",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.ShortestPath),
+			),
+			cel.MemberOverload(
+				"sbom_get_shortest_path_edgetype",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.StringType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.ShortestPath),
+			),
+			cel.MemberOverload(
+				"sbom_get_shortest_path_edgetypes",
+				[]*cel.Type{elements.DocumentType, cel.StringType, cel.StringType, cel.ListType(cel.StringType)},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.ShortestPath),
+			),
+			cel.MemberOverload(
+				"nodelist_get_shortest_path",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.ShortestPath),
+			),
+			cel.MemberOverload(
+				"nodelist_get_shortest_path_edgetype",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.StringType, cel.StringType},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.ShortestPath),
+			),
+			cel.MemberOverload(
+				"nodelist_get_shortest_path_edgetypes",
+				[]*cel.Type{elements.NodeListType, cel.StringType, cel.StringType, cel.ListType(cel.StringType)},
+				elements.NodeListType,
+				cel.FunctionBinding(functions.ShortestPath),
+			),
+		),
 	}
 
+	// Set operations between Documents and NodeLists
+	envopt = append(
+		envopt,
+		cel.Function("union", setOperationOverloads("union", functions.Union)...),
+		cel.Function("intersect", setOperationOverloads("intersect", functions.Intersection)...),
+		cel.Function("difference", setOperationOverloads("difference", functions.Difference)...),
+		cel.Function("symmetric_difference", setOperationOverloads("symmetric_difference", functions.SymmetricDifference)...),
+		cel.Function("diff", diffOverloads()...),
+
+		// with_vulnerabilities records the vulnerabilities returned by
+		// match_vulnerabilities as node properties.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"with_vulnerabilities",
+			cel.MemberOverload(
+				"sbom_withvulnerabilities_binding",
+				[]*cel.Type{elements.DocumentType, cel.ListType(cel.MapType(cel.StringType, cel.DynType))},
+				elements.DocumentType,
+				cel.BinaryBinding(functions.WithVulnerabilities),
+			),
+			cel.MemberOverload(
+				"nodelist_withvulnerabilities_binding",
+				[]*cel.Type{elements.NodeListType, cel.ListType(cel.MapType(cel.StringType, cel.DynType))},
+				elements.NodeListType,
+				cel.BinaryBinding(functions.WithVulnerabilities),
+			),
+		),
+
+		// parse_vex reads an OpenVEX document from a string
+		cel.Function(
+			"parse_vex",
+			cel.MemberOverload(
+				"protobom_parsevex_binding",
+				[]*cel.Type{elements.ProtobomType, cel.StringType}, elements.VEXType,
+				cel.BinaryBinding(functions.ParseVEX),
+			),
+		),
+
+		// status_for returns the VEX status of a vulnerability in a node
+		cel.Function(
+			"status_for",
+			cel.MemberOverload(
+				"vex_statusfor_binding",
+				[]*cel.Type{elements.VEXType, elements.NodeType, cel.StringType}, cel.StringType,
+				cel.FunctionBinding(functions.VEXStatusFor),
+			),
+		),
+
+		// without_not_affected drops the nodes that a VEX document states
+		// are not affected by a vulnerability.
+		// Overloaded in: Document and NodeList.
+		cel.Function(
+			"without_not_affected",
+			cel.MemberOverload(
+				"sbom_withoutnotaffected_binding",
+				[]*cel.Type{elements.DocumentType, elements.VEXType, cel.StringType}, elements.NodeListType,
+				cel.FunctionBinding(functions.WithoutNotAffected),
+			),
+			cel.MemberOverload(
+				"nodelist_withoutnotaffected_binding",
+				[]*cel.Type{elements.NodeListType, elements.VEXType, cel.StringType}, elements.NodeListType,
+				cel.FunctionBinding(functions.WithoutNotAffected),
+			),
+		),
+
+		// match_vulnerabilities matches the nodes against the advisories
+		// of an OSV database
+		cel.Function(
+			"match_vulnerabilities",
+			cel.MemberOverload(
+				"protobom_matchvulnerabilities_sbom_binding",
+				[]*cel.Type{elements.ProtobomType, elements.DocumentType, elements.OSVDatabaseType},
+				cel.ListType(cel.MapType(cel.StringType, cel.DynType)),
+				cel.FunctionBinding(functions.MatchVulnerabilitiesBinding),
+			),
+			cel.MemberOverload(
+				"protobom_matchvulnerabilities_nodelist_binding",
+				[]*cel.Type{elements.ProtobomType, elements.NodeListType, elements.OSVDatabaseType},
+				cel.ListType(cel.MapType(cel.StringType, cel.DynType)),
+				cel.FunctionBinding(functions.MatchVulnerabilitiesBinding),
+			),
+		),
+
+		// @where is the function that the where() macro expands to. It
+		// returns the nodes of a NodeList selected by the macro predicate.
+		cel.Function(
+			whereFunction,
+			cel.Overload(
+				"nodelist_where_binding",
+				[]*cel.Type{elements.NodeListType, cel.ListType(elements.NodeType)},
+				elements.NodeListType,
+				cel.BinaryBinding(functions.SelectNodes),
+			),
+		),
+	)
+
 	// Here we add all the functions that trigger I/O calls on the host system
 	// only if the option is enables. Most apps will not need them so we don't
 	// load them by default.
@@ -260,7 +718,117 @@ func (p *Protobom) Functions() []cel.EnvOption {
 					cel.BinaryBinding(functions.LoadSBOM),
 				),
 			),
+
+			// load_vex reads an OpenVEX document from a file
+			cel.Function(
+				"load_vex",
+				cel.MemberOverload(
+					"protobom_loadvex_binding",
+					[]*cel.Type{elements.ProtobomType, cel.StringType}, elements.VEXType,
+					cel.BinaryBinding(functions.LoadVEX),
+				),
+			),
+
+			// load_osv reads the OSV advisories in a local directory, zip
+			// file or JSON file
+			cel.Function(
+				"load_osv",
+				cel.MemberOverload(
+					"protobom_loadosv_binding",
+					[]*cel.Type{elements.ProtobomType, cel.StringType}, elements.OSVDatabaseType,
+					cel.BinaryBinding(functions.LoadOSV),
+				),
+			),
 		)
 	}
 	return envopt
 }
+
+// setOperationOverloads returns the member overloads of a set operation
+// function. Set operations take any combination of Documents and NodeLists
+// and an optional string to define the node matching mode (id or purl).
+func setOperationOverloads(name string, binding func(...ref.Val) ref.Val) []cel.FunctionOpt {
+	operands := []struct {
+		prefix string
+		t      *cel.Type
+	}{
+		{"sbom", elements.DocumentType},
+		{"nodelist", elements.NodeListType},
+	}
+
+	overloads := []cel.FunctionOpt{}
+	for _, lhs := range operands {
+		for _, rhs := range operands {
+			overloads = append(overloads,
+				cel.MemberOverload(
+					fmt.Sprintf("%s_%s_%s_binding", lhs.prefix, rhs.prefix, name),
+					[]*cel.Type{lhs.t, rhs.t}, elements.NodeListType,
+					cel.FunctionBinding(binding),
+				),
+				cel.MemberOverload(
+					fmt.Sprintf("%s_%s_%s_mode_binding", lhs.prefix, rhs.prefix, name),
+					[]*cel.Type{lhs.t, rhs.t, cel.StringType}, elements.NodeListType,
+					cel.FunctionBinding(binding),
+				),
+			)
+		}
+	}
+	return overloads
+}
+
+// depthOverloads returns the overloads of the depth functions for Documents
+// and NodeLists, with and without an edge type filter that can be a single
+// type or a list.
+func depthOverloads(name string, argType, resultType *cel.Type, binding func(...ref.Val) ref.Val) []cel.FunctionOpt {
+	overloads := []cel.FunctionOpt{}
+	for _, target := range []struct {
+		prefix string
+		t      *cel.Type
+	}{
+		{"sbom", elements.DocumentType},
+		{"nodelist", elements.NodeListType},
+	} {
+		overloads = append(overloads,
+			cel.MemberOverload(
+				fmt.Sprintf("%s_%s_binding", target.prefix, name),
+				[]*cel.Type{target.t, argType}, resultType,
+				cel.FunctionBinding(binding),
+			),
+			cel.MemberOverload(
+				fmt.Sprintf("%s_%s_edgetype_binding", target.prefix, name),
+				[]*cel.Type{target.t, argType, cel.StringType}, resultType,
+				cel.FunctionBinding(binding),
+			),
+			cel.MemberOverload(
+				fmt.Sprintf("%s_%s_edgetypes_binding", target.prefix, name),
+				[]*cel.Type{target.t, argType, cel.ListType(cel.StringType)}, resultType,
+				cel.FunctionBinding(binding),
+			),
+		)
+	}
+	return overloads
+}
+
+// diffOverloads returns the overloads of protobom.diff() for any
+// combination of Documents and NodeLists.
+func diffOverloads() []cel.FunctionOpt {
+	operands := []struct {
+		prefix string
+		t      *cel.Type
+	}{
+		{"sbom", elements.DocumentType},
+		{"nodelist", elements.NodeListType},
+	}
+
+	overloads := []cel.FunctionOpt{}
+	for _, lhs := range operands {
+		for _, rhs := range operands {
+			overloads = append(overloads, cel.MemberOverload(
+				fmt.Sprintf("protobom_diff_%s_%s_binding", lhs.prefix, rhs.prefix),
+				[]*cel.Type{elements.ProtobomType, lhs.t, rhs.t}, cel.MapType(cel.StringType, cel.DynType),
+				cel.FunctionBinding(functions.DiffBinding),
+			))
+		}
+	}
+	return overloads
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package library
+
+import (
+	"slices"
+
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/osv"
+)
+
+// CostEstimator computes the runtime cost of the protobom functions. As most
+// of them walk the SBOM graph, calls taking Documents or NodeLists cost one
+// unit for each node and edge in their arguments. Calls loading or reading
+// OSV databases cost one unit for each advisory in the database. Other calls
+// are left to the default CEL cost model.
+type CostEstimator struct{}
+
+// CallCost implements interpreter.ActualCostEstimator
+func (CostEstimator) CallCost(_, _ string, args []ref.Val, result ref.Val) *uint64 {
+	var cost uint64
+	found := false
+	vals := args
+	if result != nil {
+		if _, ok := result.Value().(*osv.Database); ok {
+			vals = append(slices.Clone(args), result)
+		}
+	}
+	for _, arg := range vals {
+		switch v := arg.Value().(type) {
+		case *sbom.Document:
+			cost += uint64(len(v.GetNodeList().GetNodes()) + len(v.GetNodeList().GetEdges()))
+		case *sbom.NodeList:
+			cost += uint64(len(v.GetNodes()) + len(v.GetEdges()))
+		case *osv.Database:
+			cost += uint64(len(v.Vulnerabilities))
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	// Calls always cost at least one unit
+	cost = max(cost, 1)
+	return &cost
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package library
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+
+	"github.com/protobom/cel/pkg/functions"
+)
+
+// InterruptibleGraphFunctions returns a program option that makes the graph
+// traversal functions check for interrupts as they walk the graph. Add it to
+// programs evaluated with ContextEval and cel.InterruptCheckFrequency, without
+// an interrupt source the regular bindings are used.
+func InterruptibleGraphFunctions() cel.ProgramOption {
+	return cel.CustomDecoratorV2(decorateInterruptible)
+}
+
+// decorateInterruptible replaces the calls to the graph traversal functions
+// with versions that check the evaluation frame for interrupts as they walk
+// the graph.
+func decorateInterruptible(i interpreter.InterpretableV2) (interpreter.InterpretableV2, error) {
+	call, ok := i.(interpreter.InterpretableCall)
+	if !ok {
+		return i, nil
+	}
+	impl, ok := functions.InterruptibleBindings[call.Function()]
+	if !ok {
+		return i, nil
+	}
+	return &interruptibleCall{InterpretableCall: call, impl: impl}, nil
+}
+
+// interruptibleCall is a function call evaluated with an interruptible
+// binding. It keeps implementing InterpretableCall so that runtime cost
+// tracking still sees the call.
+type interruptibleCall struct {
+	interpreter.InterpretableCall
+	impl functions.InterruptibleFunction
+}
+
+// Eval implements interpreter.Interpretable
+func (c *interruptibleCall) Eval(act interpreter.Activation) ref.Val {
+	return c.Exec(interpreter.AsFrame(act))
+}
+
+// Exec evaluates the arguments and calls the interruptible binding
+func (c *interruptibleCall) Exec(frame *interpreter.ExecutionFrame) ref.Val {
+	argVals := make([]ref.Val, 0, len(c.Args()))
+	for _, arg := range c.Args() {
+		v := arg.Exec(frame)
+		if types.IsUnknownOrError(v) {
+			return v
+		}
+		argVals = append(argVals, v)
+	}
+	return types.LabelErrNode(c.ID(), c.impl(frame.CheckInterrupt, argVals...))
+}
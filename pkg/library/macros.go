@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package library
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+)
+
+const (
+	// whereFunction is the internal function the where() macro expands to.
+	// Its name cannot be parsed so users cannot call it directly.
+	whereFunction = "@where"
+
+	// whereNodeListVar holds the macro target while the nodes are filtered
+	whereNodeListVar = "@where_nodelist"
+
+	// unusedIterVar is the iteration variable of the comprehension that
+	// binds the target, it never iterates.
+	unusedIterVar = "#unused"
+)
+
+// Macros returns the macros that the protobom library adds to the
+// environment.
+func (*Protobom) Macros() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Macros(
+			// where filters the nodes of a Document or NodeList and returns
+			// a NodeList keeping the edges among the selected nodes:
+			//
+			//	sboms[0].where(n, n.name.startsWith("k8s.io"))
+			cel.ReceiverMacro("where", 2, expandWhere),
+		),
+	}
+}
+
+// expandWhere expands target.where(n, predicate) into:
+//
+//	@where(nl, nl.get_nodes().filter(n, predicate))
+//
+// with nl bound to target.to_node_list() so that the target is only
+// evaluated once.
+func expandWhere(eh cel.MacroExprFactory, target ast.Expr, args []ast.Expr) (ast.Expr, *cel.Error) {
+	if args[0].Kind() != ast.IdentKind {
+		return nil, eh.NewError(args[0].ID(), "where() variable must be a simple identifier")
+	}
+	iterVar := args[0].AsIdent()
+	accu := eh.AccuIdentName()
+	if iterVar == accu || iterVar == whereNodeListVar {
+		return nil, eh.NewError(args[0].ID(), "iteration variable overwrites an internal variable")
+	}
+
+	step := eh.NewCall(operators.Add, eh.NewAccuIdent(), eh.NewList(eh.NewIdent(iterVar)))
+	step = eh.NewCall(operators.Conditional, args[1], step, eh.NewAccuIdent())
+	filter := eh.NewComprehension(
+		eh.NewMemberCall("get_nodes", eh.NewIdent(whereNodeListVar)),
+		iterVar, accu, eh.NewList(), eh.NewLiteral(types.True), step, eh.NewAccuIdent(),
+	)
+
+	return eh.NewComprehension(
+		eh.NewList(),
+		unusedIterVar,
+		whereNodeListVar,
+		eh.NewMemberCall("to_node_list", target),
+		eh.NewLiteral(types.False),
+		eh.NewIdent(whereNodeListVar),
+		eh.NewCall(whereFunction, eh.NewIdent(whereNodeListVar), filter),
+	), nil
+}
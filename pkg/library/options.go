@@ -17,12 +17,17 @@ type Options struct {
 
 	// DocsVarName is the name of the variable that holds the loaded SBOMs.
 	DocsVarName string
+
+	// DocsByNameVarName is the name of the variable that holds the loaded
+	// SBOMs keyed by their file path or document name.
+	DocsByNameVarName string
 }
 
 var DefaultOptions = Options{
-	EnableIO:        false,
-	ProtobomVarName: "protobom",
-	DocsVarName:     "sboms",
+	EnableIO:          false,
+	ProtobomVarName:   "protobom",
+	DocsVarName:       "sboms",
+	DocsByNameVarName: "sbom_by_name",
 }
 
 type OptFunc func(*Options)
@@ -44,3 +49,9 @@ func WithDocsVarName(name string) OptFunc {
 		o.DocsVarName = name
 	}
 }
+
+func WithDocsByNameVarName(name string) OptFunc {
+	return func(o *Options) {
+		o.DocsByNameVarName = name
+	}
+}
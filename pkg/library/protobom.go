@@ -53,6 +53,8 @@ func (*Protobom) Types() []cel.EnvOption {
 		cel.Types(elements.PropertyType),
 		cel.Types(elements.SourceDataType),
 		cel.Types(elements.ToolType),
+		cel.Types(elements.VEXType),
+		cel.Types(elements.OSVDatabaseType),
 		cel.Types(&sbom.Document{}),
 		cel.Types(&sbom.Edge{}),
 		cel.Types(&sbom.ExternalReference{}),
@@ -70,7 +72,8 @@ func (*Protobom) Types() []cel.EnvOption {
 // environment when the library is included
 func (p *Protobom) Variables() []cel.EnvOption {
 	return []cel.EnvOption{
-		cel.Variable(p.Options.DocsVarName, cel.MapType(cel.IntType, elements.DocumentType)),
+		cel.Variable(p.Options.DocsVarName, cel.ListType(elements.DocumentType)),
+		cel.Variable(p.Options.DocsByNameVarName, cel.MapType(cel.StringType, elements.DocumentType)),
 		cel.Variable(p.Options.ProtobomVarName, elements.ProtobomType),
 	}
 }
@@ -90,6 +93,7 @@ func (p *Protobom) CompileOptions() []cel.EnvOption {
 		p.Types(),
 		p.Variables(),
 		p.Functions(),
+		p.Macros(),
 		p.TypeAdapters(),
 	)
 }
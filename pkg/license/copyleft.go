@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package license
+
+// copyleftPatterns lists the licenses that require derived works (or
+// modifications to the covered files in the case of the weak copyleft
+// licenses) to be distributed under the same terms. The SPDX license list
+// does not record this property so it is maintained here.
+var copyleftPatterns = []string{
+	// Strong copyleft
+	"AGPL-*",
+	"GPL-*",
+	"EUPL-*",
+	"OSL-*",
+	"CECILL-1.*",
+	"CECILL-2.*",
+	"CC-BY-SA-*",
+	"CC-BY-NC-SA-*",
+	"ODbL-*",
+	"Sleepycat",
+	"RPL-*",
+	"RPSL-1.0",
+	"SSPL-*",
+	"QPL-*",
+	"GFDL-*",
+	"CERN-OHL-S-*",
+	"NPOSL-3.0",
+	// Weak copyleft
+	"LGPL-*",
+	"MPL-*",
+	"EPL-*",
+	"CDDL-*",
+	"CPL-1.0",
+	"IPL-1.0",
+	"CPAL-1.0",
+	"APSL-*",
+	"MS-RL",
+	"CECILL-C",
+	"CERN-OHL-W-*",
+}
+
+// IsCopyleft returns true if the license identifier is a copyleft license
+func IsCopyleft(id string) bool {
+	for _, p := range copyleftPatterns {
+		if ok, err := Match(p, id); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package license
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Operators supported in license expressions
+const (
+	OperatorAnd  = "AND"
+	OperatorOr   = "OR"
+	OperatorWith = "WITH"
+)
+
+var (
+	licenseIDRegex  = regexp.MustCompile(`^[A-Za-z0-9.-]+$`)
+	licenseRefRegex = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.-]+:)?LicenseRef-[A-Za-z0-9.-]+$`)
+)
+
+// Expression is a node in a parsed SPDX license expression. Compound
+// expressions have an Operator and both Left and Right set, simple
+// expressions have a License identifier and optionally the or-later flag
+// and an exception.
+type Expression struct {
+	Operator  string
+	Left      *Expression
+	Right     *Expression
+	License   string
+	OrLater   bool
+	Exception string
+}
+
+// IsLicenseRef returns true if the expression is a user defined license
+// reference (LicenseRef- or DocumentRef-)
+func (e *Expression) IsLicenseRef() bool {
+	return e.Operator == "" && licenseRefRegex.MatchString(e.License)
+}
+
+// String renders the expression back in SPDX syntax
+func (e *Expression) String() string {
+	if e.Operator == "" {
+		s := e.License
+		if e.OrLater {
+			s += "+"
+		}
+		if e.Exception != "" {
+			s += " " + OperatorWith + " " + e.Exception
+		}
+		return s
+	}
+
+	left, right := e.Left.String(), e.Right.String()
+	// OR binds looser than AND so it needs parenthesis when nested
+	if e.Operator == OperatorAnd {
+		if e.Left.Operator == OperatorOr {
+			left = "(" + left + ")"
+		}
+		if e.Right.Operator == OperatorOr {
+			right = "(" + right + ")"
+		}
+	}
+	return left + " " + e.Operator + " " + right
+}
+
+// walk calls f on each of the simple expressions in the tree
+func (e *Expression) walk(f func(*Expression)) {
+	if e.Operator == "" {
+		f(e)
+		return
+	}
+	e.Left.walk(f)
+	e.Right.walk(f)
+}
+
+// LicenseIDs returns the license identifiers in the expression in order of
+// appearance, without duplicates. The or-later suffix and exceptions are
+// not included.
+func (e *Expression) LicenseIDs() []string {
+	ret := []string{}
+	e.walk(func(s *Expression) {
+		if !slices.Contains(ret, s.License) {
+			ret = append(ret, s.License)
+		}
+	})
+	return ret
+}
+
+// Validate checks all the identifiers in the expression against the SPDX
+// license list. License references are always valid.
+func (e *Expression) Validate() error {
+	list := DefaultList()
+	errs := []error{}
+	e.walk(func(s *Expression) {
+		if _, ok := list.License(s.License); !ok && !s.IsLicenseRef() {
+			errs = append(errs, fmt.Errorf("unknown license identifier %q", s.License))
+		}
+		if s.Exception == "" {
+			return
+		}
+		if _, ok := list.Exception(s.Exception); !ok {
+			errs = append(errs, fmt.Errorf("unknown license exception %q", s.Exception))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// options returns the expression in disjunctive normal form: a list of the
+// alternative sets of licenses that fulfill the expression.
+func (e *Expression) options() [][]*Expression {
+	switch e.Operator {
+	case OperatorOr:
+		return append(e.Left.options(), e.Right.options()...)
+	case OperatorAnd:
+		ret := [][]*Expression{}
+		for _, l := range e.Left.options() {
+			for _, r := range e.Right.options() {
+				ret = append(ret, append(slices.Clone(l), r...))
+			}
+		}
+		return ret
+	default:
+		return [][]*Expression{{e}}
+	}
+}
+
+// baseID returns the license identifier without the GNU -only and
+// -or-later suffixes, normalized to lowercase to compare identifiers.
+func baseID(s *Expression) string {
+	id := strings.ToLower(s.License)
+	id = strings.TrimSuffix(id, "-or-later")
+	id = strings.TrimSuffix(id, "-only")
+	return id
+}
+
+// versionRegex splits a base license identifier into its family and version
+var versionRegex = regexp.MustCompile(`^(.+)-(\d+(?:\.\d+)*)$`)
+
+// isOrLater returns true if the simple expression accepts later versions of
+// the license, either with the + operator or the GNU -or-later suffix.
+func isOrLater(s *Expression) bool {
+	return s.OrLater || strings.HasSuffix(strings.ToLower(s.License), "-or-later")
+}
+
+// compareVersions compares two dotted numeric versions, missing components
+// count as zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// covers returns true if an allowed simple expression covers the required
+// one. A required or-later license (GPL-2.0+ or GPL-2.0-or-later) is also
+// covered by the same license at a later version (eg GPL-3.0-only). The
+// range of the allowed license is not taken into account, picking the
+// allowed version is always possible.
+func covers(allowed, required *Expression) bool {
+	if !strings.EqualFold(allowed.Exception, required.Exception) {
+		return false
+	}
+	allowedID, requiredID := baseID(allowed), baseID(required)
+	if allowedID == requiredID {
+		return true
+	}
+	if !isOrLater(required) {
+		return false
+	}
+	a, r := versionRegex.FindStringSubmatch(allowedID), versionRegex.FindStringSubmatch(requiredID)
+	if a == nil || r == nil || a[1] != r[1] {
+		return false
+	}
+	return compareVersions(a[2], r[2]) >= 0
+}
+
+// Satisfies returns true if the expression can be fulfilled using only the
+// licenses in the allowed list. The allowed entries are license
+// identifiers, optionally with an exception (eg "GPL-2.0 WITH
+// Classpath-exception-2.0").
+func (e *Expression) Satisfies(allowed []string) (bool, error) {
+	allowedExpressions := make([]*Expression, 0, len(allowed))
+	for _, a := range allowed {
+		ae, err := Parse(a)
+		if err != nil {
+			return false, fmt.Errorf("parsing allowed license: %w", err)
+		}
+		if ae.Operator != "" {
+			return false, fmt.Errorf("allowed license %q must not be a compound expression", a)
+		}
+		allowedExpressions = append(allowedExpressions, ae)
+	}
+
+	for _, option := range e.options() {
+		if !slices.ContainsFunc(option, func(required *Expression) bool {
+			return !slices.ContainsFunc(allowedExpressions, func(a *Expression) bool {
+				return covers(a, required)
+			})
+		}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsCopyleft returns true if all the alternatives to fulfill the license
+// expression include a copyleft license.
+func (e *Expression) IsCopyleft() bool {
+	for _, option := range e.options() {
+		if !slices.ContainsFunc(option, func(s *Expression) bool {
+			return IsCopyleft(s.License)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match checks if a license identifier matches a glob pattern such as
+// "GPL-*". Matching is case insensitive.
+func Match(pattern, id string) (bool, error) {
+	return path.Match(strings.ToLower(pattern), strings.ToLower(id))
+}
+
+// Parse parses an SPDX license expression. Known license and exception
+// identifiers are normalized to their canonical case, unknown identifiers
+// are preserved (use Validate to check them).
+func Parse(s string) (*Expression, error) {
+	p := &parser{tokens: tokenize(s)}
+	if len(p.tokens) == 0 {
+		return nil, errors.New("empty license expression")
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("parsing license expression %q: %w", s, err)
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, fmt.Errorf("parsing license expression %q: unexpected %q", s, tok)
+	}
+	return e, nil
+}
+
+// tokenize splits a license expression into its tokens
+func tokenize(s string) []string {
+	tokens := []string{}
+	current := strings.Builder{}
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range s {
+		switch r {
+		case '(', ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case ' ', '\t', '\n', '\r':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// parser is a recursive descent parser of license expressions. WITH binds
+// tighter than AND which binds tighter than OR.
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// peek returns the next token without consuming it
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// next consumes the next token
+func (p *parser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+// isOperator checks if a token is the specified operator. The spec requires
+// operators to be uppercase but lowercase ones are common in the wild.
+func isOperator(tok, op string) bool {
+	return strings.EqualFold(tok, op)
+}
+
+func (p *parser) parseOr() (*Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isOperator(p.peek(), OperatorOr) {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Expression{Operator: OperatorOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (*Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for isOperator(p.peek(), OperatorAnd) {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &Expression{Operator: OperatorAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseTerm parses a parenthesized expression or a simple license
+// expression, optionally with an exception.
+func (p *parser) parseTerm() (*Expression, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, errors.New("unexpected end of expression")
+	case tok == "(":
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		return e, nil
+	case tok == ")" || isOperator(tok, OperatorAnd) || isOperator(tok, OperatorOr) || isOperator(tok, OperatorWith):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+
+	e, err := parseLicense(tok)
+	if err != nil {
+		return nil, err
+	}
+
+	if isOperator(p.peek(), OperatorWith) {
+		p.next()
+		exc := p.next()
+		if exc == "" || !licenseIDRegex.MatchString(exc) {
+			return nil, fmt.Errorf("invalid license exception %q", exc)
+		}
+		if known, ok := DefaultList().Exception(exc); ok {
+			exc = known.ID
+		}
+		e.Exception = exc
+	}
+	return e, nil
+}
+
+// parseLicense parses a license identifier or reference
+func parseLicense(tok string) (*Expression, error) {
+	if licenseRefRegex.MatchString(tok) {
+		return &Expression{License: tok}, nil
+	}
+
+	e := &Expression{}
+	if id, ok := strings.CutSuffix(tok, "+"); ok {
+		e.OrLater = true
+		tok = id
+	}
+	if !licenseIDRegex.MatchString(tok) {
+		return nil, fmt.Errorf("invalid license identifier %q", tok)
+	}
+	if known, ok := DefaultList().License(tok); ok {
+		tok = known.ID
+	}
+	e.License = tok
+	return e, nil
+}
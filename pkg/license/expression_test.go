@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		expected string
+		ids      []string
+		mustErr  bool
+	}{
+		{"simple", "MIT", "MIT", []string{"MIT"}, false},
+		{"canonical-case", "apache-2.0", "Apache-2.0", []string{"Apache-2.0"}, false},
+		{"or-later", "GPL-2.0+", "GPL-2.0+", []string{"GPL-2.0"}, false},
+		{"with", "GPL-2.0-only WITH classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0", []string{"GPL-2.0-only"}, false},
+		{"and-or", "MIT OR Apache-2.0 AND BSD-3-Clause", "MIT OR Apache-2.0 AND BSD-3-Clause", []string{"MIT", "Apache-2.0", "BSD-3-Clause"}, false},
+		{"parens", "(MIT OR Apache-2.0) AND BSD-3-Clause", "(MIT OR Apache-2.0) AND BSD-3-Clause", []string{"MIT", "Apache-2.0", "BSD-3-Clause"}, false},
+		{"nested-parens", "((MIT))", "MIT", []string{"MIT"}, false},
+		{"lowercase-operators", "MIT or Apache-2.0", "MIT OR Apache-2.0", []string{"MIT", "Apache-2.0"}, false},
+		{"license-ref", "LicenseRef-custom AND DocumentRef-ext:LicenseRef-other", "LicenseRef-custom AND DocumentRef-ext:LicenseRef-other", []string{"LicenseRef-custom", "DocumentRef-ext:LicenseRef-other"}, false},
+		{"duplicates", "MIT AND (MIT OR ISC)", "MIT AND (MIT OR ISC)", []string{"MIT", "ISC"}, false},
+		{"unknown-id", "Foo-1.0", "Foo-1.0", []string{"Foo-1.0"}, false},
+		{"empty", " ", "", nil, true},
+		{"dangling-and", "MIT AND", "", nil, true},
+		{"leading-or", "OR MIT", "", nil, true},
+		{"unbalanced", "(MIT OR ISC", "", nil, true},
+		{"extra-paren", "MIT)", "", nil, true},
+		{"missing-operator", "MIT ISC", "", nil, true},
+		{"with-compound", "(MIT OR ISC) WITH Classpath-exception-2.0", "", nil, true},
+		{"with-nothing", "MIT WITH", "", nil, true},
+		{"invalid-chars", "MIT/ISC", "", nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, e.String())
+			require.Equal(t, tc.ids, e.LicenseIDs())
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		expr    string
+		mustErr bool
+	}{
+		{"MIT OR Apache-2.0", false},
+		{"GPL-2.0-or-later WITH Classpath-exception-2.0", false},
+		{"LicenseRef-mine", false},
+		{"Foo-1.0 OR MIT", true},
+		{"MIT WITH Foo-exception", true},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			require.NoError(t, err)
+			if tc.mustErr {
+				require.Error(t, e.Validate())
+			} else {
+				require.NoError(t, e.Validate())
+			}
+		})
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		allowed  []string
+		expected bool
+		mustErr  bool
+	}{
+		{"simple", "MIT", []string{"MIT"}, true, false},
+		{"simple-no", "MIT", []string{"Apache-2.0"}, false, false},
+		{"case", "mit", []string{"MIT"}, true, false},
+		{"or", "MIT OR GPL-3.0-only", []string{"MIT"}, true, false},
+		{"and", "MIT AND GPL-3.0-only", []string{"MIT"}, false, false},
+		{"and-all", "MIT AND GPL-3.0-only", []string{"MIT", "GPL-3.0-only"}, true, false},
+		{"mixed", "(MIT OR ISC) AND (Apache-2.0 OR GPL-2.0-only)", []string{"ISC", "Apache-2.0"}, true, false},
+		{"mixed-no", "(MIT OR ISC) AND (Apache-2.0 OR GPL-2.0-only)", []string{"ISC", "BSD-3-Clause"}, false, false},
+		{"or-later", "GPL-2.0+", []string{"GPL-2.0-only"}, true, false},
+		{"gnu-suffix", "GPL-2.0-or-later", []string{"GPL-2.0"}, true, false},
+		{"or-later-newer", "GPL-2.0+", []string{"GPL-3.0-only"}, true, false},
+		{"gnu-suffix-newer", "LGPL-2.0-or-later", []string{"LGPL-2.1-only"}, true, false},
+		{"or-later-older", "GPL-3.0-or-later", []string{"GPL-2.0-only"}, false, false},
+		{"or-later-family", "GPL-2.0+", []string{"LGPL-3.0-only"}, false, false},
+		{"only-newer", "GPL-2.0-only", []string{"GPL-3.0-only"}, false, false},
+		{"or-later-with", "GPL-2.0+ WITH Classpath-exception-2.0", []string{"GPL-3.0-only"}, false, false},
+		{"with", "GPL-2.0-only WITH Classpath-exception-2.0", []string{"GPL-2.0-only WITH Classpath-exception-2.0"}, true, false},
+		{"with-missing", "GPL-2.0-only WITH Classpath-exception-2.0", []string{"GPL-2.0-only"}, false, false},
+		{"license-ref", "LicenseRef-internal", []string{"LicenseRef-internal"}, true, false},
+		{"compound-allowed", "MIT", []string{"MIT OR ISC"}, false, true},
+		{"invalid-allowed", "MIT", []string{"MIT AND"}, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			require.NoError(t, err)
+			res, err := e.Satisfies(tc.allowed)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestIsCopyleft(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		expected bool
+	}{
+		{"MIT", false},
+		{"GPL-3.0-only", true},
+		{"LGPL-2.1-or-later", true},
+		{"MPL-2.0", true},
+		{"MIT AND GPL-2.0-only", true},
+		{"MIT OR GPL-2.0-only", false},
+		{"(MIT OR LGPL-2.1-only) AND AGPL-3.0-only", true},
+		{"Apache-2.0 OR BSD-3-Clause", false},
+		{"CECILL-B", false},
+		{"LicenseRef-internal", false},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := Parse(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, e.IsCopyleft())
+		})
+	}
+}
+
+func TestDefaultList(t *testing.T) {
+	list := DefaultList()
+	require.NotEmpty(t, list.Version)
+
+	lic, ok := list.License("apache-2.0")
+	require.True(t, ok)
+	require.Equal(t, "Apache-2.0", lic.ID)
+	require.True(t, lic.OsiApproved)
+	require.True(t, lic.FsfLibre)
+	require.False(t, lic.Deprecated)
+
+	lic, ok = list.License("GPL-2.0")
+	require.True(t, ok)
+	require.True(t, lic.Deprecated)
+
+	_, ok = list.License("Foo-1.0")
+	require.False(t, ok)
+
+	exc, ok := list.Exception("LLVM-exception")
+	require.True(t, ok)
+	require.Equal(t, "LLVM-exception", exc.ID)
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+// Package license implements SPDX license expression parsing and evaluation
+// using an offline copy of the SPDX license list.
+package license
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// licenseListData is a trimmed down copy of the SPDX license list data. It
+// can be refreshed with make update-licenses.
+//
+//go:embed licenses.json
+var licenseListData []byte
+
+// License is an entry in the SPDX license list
+type License struct {
+	ID          string `json:"licenseId"`
+	Deprecated  bool   `json:"isDeprecatedLicenseId"`
+	OsiApproved bool   `json:"isOsiApproved"`
+	FsfLibre    bool   `json:"isFsfLibre"`
+}
+
+// Exception is an entry in the SPDX license exceptions list
+type Exception struct {
+	ID         string `json:"licenseExceptionId"`
+	Deprecated bool   `json:"isDeprecatedLicenseId"`
+}
+
+// List is the SPDX license list
+type List struct {
+	Version    string      `json:"licenseListVersion"`
+	Licenses   []License   `json:"licenses"`
+	Exceptions []Exception `json:"exceptions"`
+
+	licenses   map[string]*License
+	exceptions map[string]*Exception
+}
+
+// DefaultList returns the license list bundled in the package
+var DefaultList = sync.OnceValue(func() *List {
+	list := &List{}
+	if err := json.Unmarshal(licenseListData, list); err != nil {
+		panic("parsing embedded SPDX license list: " + err.Error())
+	}
+
+	list.licenses = make(map[string]*License, len(list.Licenses))
+	for i := range list.Licenses {
+		list.licenses[strings.ToLower(list.Licenses[i].ID)] = &list.Licenses[i]
+	}
+	list.exceptions = make(map[string]*Exception, len(list.Exceptions))
+	for i := range list.Exceptions {
+		list.exceptions[strings.ToLower(list.Exceptions[i].ID)] = &list.Exceptions[i]
+	}
+	return list
+})
+
+// License looks up a license by its identifier. The lookup is case
+// insensitive as mandated by the SPDX spec.
+func (l *List) License(id string) (*License, bool) {
+	lic, ok := l.licenses[strings.ToLower(id)]
+	return lic, ok
+}
+
+// Exception looks up a license exception by its identifier
+func (l *List) Exception(id string) (*Exception, bool) {
+	exc, ok := l.exceptions[strings.ToLower(id)]
+	return exc, ok
+}
@@ -0,0 +1,390 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+// Package osv reads vulnerability data in the Open Source Vulnerability (OSV)
+// format from local directories and zipped exports.
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// Range types evaluated when matching versions. GIT ranges are ignored as
+// they list commits, not versions.
+const (
+	RangeTypeSemver    = "SEMVER"
+	RangeTypeEcosystem = "ECOSYSTEM"
+	RangeTypeGit       = "GIT"
+)
+
+// Vulnerability is an OSV advisory
+type Vulnerability struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Withdrawn        string           `json:"withdrawn"`
+	Severity         []Severity       `json:"severity"`
+	Affected         []Affected       `json:"affected"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+// Severity is a severity score of a vulnerability, eg a CVSS vector
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// DatabaseSpecific holds the fields of the database specific data that are
+// read from the advisories.
+type DatabaseSpecific struct {
+	Severity string `json:"severity"`
+}
+
+// Affected is a package affected by a vulnerability
+type Affected struct {
+	Package  Package  `json:"package"`
+	Ranges   []Range  `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+// Package identifies an affected package
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl"`
+}
+
+// Range is a list of events that mark the affected versions of a package
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event is a version where a package becomes affected or stops being
+// affected. Only one of the fields is set.
+type Event struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+// version returns the version of the event
+func (e *Event) version() string {
+	for _, v := range []string{e.Introduced, e.Fixed, e.LastAffected, e.Limit} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// CompareFunc compares two versions returning -1, 0 or 1
+type CompareFunc func(a, b string) (int, error)
+
+// SeverityLabel returns the severity of the vulnerability. It is the rating
+// in the database specific data when set (eg HIGH) or else the first score
+// (eg a CVSS vector).
+func (v *Vulnerability) SeverityLabel() string {
+	if v.DatabaseSpecific.Severity != "" {
+		return v.DatabaseSpecific.Severity
+	}
+	for _, s := range v.Severity {
+		if s.Score != "" {
+			return s.Score
+		}
+	}
+	return ""
+}
+
+// Affects checks if a version of the package is affected. It returns the
+// versions fixing the vulnerability in the ranges that include the version.
+// Ranges whose versions cannot be compared are skipped.
+func (a *Affected) Affects(version string, compare CompareFunc) (affected bool, fixedIn []string) {
+	for _, v := range a.Versions {
+		if v == version {
+			affected = true
+			break
+		}
+		if c, err := compare(version, v); err == nil && c == 0 {
+			affected = true
+			break
+		}
+	}
+
+	fixedIn = []string{}
+	for _, r := range a.Ranges {
+		if r.Type != RangeTypeSemver && r.Type != RangeTypeEcosystem {
+			continue
+		}
+		if !r.contains(version, compare) {
+			continue
+		}
+		affected = true
+		for _, e := range r.Events {
+			if e.Fixed != "" && !slices.Contains(fixedIn, e.Fixed) {
+				fixedIn = append(fixedIn, e.Fixed)
+			}
+		}
+	}
+	return affected, fixedIn
+}
+
+// contains evaluates the range events in version order to check if a
+// version is affected.
+func (r *Range) contains(version string, compare CompareFunc) bool {
+	events := slices.Clone(r.Events)
+	var sortErr error
+	slices.SortStableFunc(events, func(a, b Event) int {
+		// The zero introduced event sorts before any version
+		if a.Introduced == "0" || b.Introduced == "0" {
+			switch {
+			case a.Introduced == b.Introduced:
+				return 0
+			case a.Introduced == "0":
+				return -1
+			default:
+				return 1
+			}
+		}
+		c, err := compare(a.version(), b.version())
+		if err != nil {
+			sortErr = err
+		}
+		return c
+	})
+	if sortErr != nil {
+		return false
+	}
+
+	affected := false
+	for _, e := range events {
+		if e.Introduced == "0" {
+			affected = true
+			continue
+		}
+		c, err := compare(version, e.version())
+		if err != nil {
+			return false
+		}
+		switch {
+		case e.Introduced != "" && c >= 0:
+			affected = true
+		case (e.Fixed != "" || e.Limit != "") && c >= 0:
+			affected = false
+		case e.LastAffected != "" && c > 0:
+			affected = false
+		}
+	}
+	return affected
+}
+
+// ecosystemPurlTypes maps the OSV ecosystems to package URL types
+var ecosystemPurlTypes = map[string]string{
+	"crates.io":      packageurl.TypeCargo,
+	"Go":             packageurl.TypeGolang,
+	"Hackage":        packageurl.TypeHackage,
+	"Hex":            packageurl.TypeHex,
+	"Maven":          packageurl.TypeMaven,
+	"npm":            packageurl.TypeNPM,
+	"NuGet":          packageurl.TypeNuget,
+	"Packagist":      packageurl.TypeComposer,
+	"Pub":            packageurl.TypePub,
+	"PyPI":           packageurl.TypePyPi,
+	"RubyGems":       packageurl.TypeGem,
+	"SwiftURL":       packageurl.TypeSwift,
+	"GitHub Actions": "githubactions",
+}
+
+// PackageKey returns the key used to match the affected package against
+// package URLs: its package URL without version, qualifiers or subpath.
+// The package URL is built from the ecosystem and name when the advisory
+// does not include one.
+func (p *Package) PackageKey() string {
+	if p.Purl != "" {
+		return PurlKey(p.Purl)
+	}
+
+	purlType, ok := ecosystemPurlTypes[p.Ecosystem]
+	if !ok || p.Name == "" {
+		return ""
+	}
+
+	namespace, name := "", p.Name
+	switch purlType {
+	case packageurl.TypeMaven:
+		if group, artifact, ok := strings.Cut(p.Name, ":"); ok {
+			namespace, name = group, artifact
+		}
+	default:
+		if i := strings.LastIndex(p.Name, "/"); i > 0 {
+			namespace, name = p.Name[:i], p.Name[i+1:]
+		}
+	}
+	return PurlKey(packageurl.NewPackageURL(purlType, namespace, name, "", nil, "").ToString())
+}
+
+// PurlKey returns a package URL without its version, qualifiers and subpath
+// in its canonical form. Returns an empty string if the purl is invalid.
+func PurlKey(purl string) string {
+	p, err := packageurl.FromString(purl)
+	if err != nil {
+		return ""
+	}
+	p.Version = ""
+	p.Qualifiers = nil
+	p.Subpath = ""
+	if p.Type == packageurl.TypePyPi {
+		p.Name = strings.ToLower(strings.ReplaceAll(p.Name, "_", "-"))
+	}
+	return p.ToString()
+}
+
+// AffectedPackage is an affected package entry along with its advisory
+type AffectedPackage struct {
+	Vulnerability *Vulnerability
+	Affected      *Affected
+}
+
+// Database is a collection of OSV advisories indexed by package
+type Database struct {
+	Vulnerabilities []*Vulnerability
+
+	packages map[string][]AffectedPackage
+}
+
+// NewDatabase returns an empty database
+func NewDatabase() *Database {
+	return &Database{
+		Vulnerabilities: []*Vulnerability{},
+		packages:        map[string][]AffectedPackage{},
+	}
+}
+
+// Add adds an advisory to the database. Withdrawn advisories are ignored.
+func (db *Database) Add(v *Vulnerability) {
+	if v.Withdrawn != "" {
+		return
+	}
+	db.Vulnerabilities = append(db.Vulnerabilities, v)
+	for i := range v.Affected {
+		key := v.Affected[i].Package.PackageKey()
+		if key == "" {
+			continue
+		}
+		db.packages[key] = append(db.packages[key], AffectedPackage{Vulnerability: v, Affected: &v.Affected[i]})
+	}
+}
+
+// Lookup returns the affected package entries matching a package URL. The
+// version in the purl is ignored.
+func (db *Database) Lookup(purl string) []AffectedPackage {
+	key := PurlKey(purl)
+	if key == "" {
+		return nil
+	}
+	return db.packages[key]
+}
+
+// Parse reads a single OSV advisory
+func Parse(data []byte) (*Vulnerability, error) {
+	v := &Vulnerability{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("parsing OSV advisory: %w", err)
+	}
+	if v.ID == "" {
+		return nil, fmt.Errorf("parsing OSV advisory: missing id")
+	}
+	return v, nil
+}
+
+// Load reads OSV advisories from a path. The path can be a directory, which
+// is searched recursively for JSON files, a zip file like the exports
+// published by osv.dev or a single JSON advisory.
+func Load(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening OSV data: %w", err)
+	}
+
+	db := NewDatabase()
+	switch {
+	case info.IsDir():
+		err = loadDirectory(db, path)
+	case strings.EqualFold(filepath.Ext(path), ".zip"):
+		err = loadZip(db, path)
+	default:
+		err = loadFile(db, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// loadFile adds a JSON advisory file to the database
+func loadFile(db *Database, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading OSV advisory: %w", err)
+	}
+	v, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	db.Add(v)
+	return nil
+}
+
+// loadDirectory adds the JSON advisories found in a directory tree
+func loadDirectory(db *Database, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+		return loadFile(db, path)
+	})
+}
+
+// loadZip adds the JSON advisories in a zip file
+func loadZip(db *Database, path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("opening OSV zip file: %w", err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		v, err := Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		db.Add(v)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package osv
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// compareDotted compares dotted numeric versions for testing
+func compareDotted(a, b string) (int, error) {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		var err error
+		if i < len(pa) {
+			if na, err = strconv.Atoi(pa[i]); err != nil {
+				return 0, err
+			}
+		}
+		if i < len(pb) {
+			if nb, err = strconv.Atoi(pb[i]); err != nil {
+				return 0, err
+			}
+		}
+		switch {
+		case na < nb:
+			return -1, nil
+		case na > nb:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func TestAffects(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		affected Affected
+		version  string
+		expected bool
+		fixedIn  []string
+	}{
+		{
+			"introduced-zero",
+			Affected{Ranges: []Range{{Type: RangeTypeSemver, Events: []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}}}},
+			"1.1.9", true, []string{"1.2.0"},
+		},
+		{
+			"fixed",
+			Affected{Ranges: []Range{{Type: RangeTypeSemver, Events: []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}}}},
+			"1.2.0", false, []string{},
+		},
+		{
+			"multiple-ranges",
+			Affected{Ranges: []Range{{Type: RangeTypeEcosystem, Events: []Event{
+				{Introduced: "1.0"}, {Fixed: "1.5"}, {Introduced: "2.0"}, {Fixed: "2.3"},
+			}}}},
+			"2.1", true, []string{"1.5", "2.3"},
+		},
+		{
+			"between-ranges",
+			Affected{Ranges: []Range{{Type: RangeTypeEcosystem, Events: []Event{
+				{Fixed: "2.3"}, {Introduced: "2.0"}, {Fixed: "1.5"}, {Introduced: "1.0"},
+			}}}},
+			"1.7", false, []string{},
+		},
+		{
+			"last-affected",
+			Affected{Ranges: []Range{{Type: RangeTypeSemver, Events: []Event{{Introduced: "1.0"}, {LastAffected: "1.4"}}}}},
+			"1.4", true, []string{},
+		},
+		{
+			"after-last-affected",
+			Affected{Ranges: []Range{{Type: RangeTypeSemver, Events: []Event{{Introduced: "1.0"}, {LastAffected: "1.4"}}}}},
+			"1.4.1", false, []string{},
+		},
+		{
+			"no-fix",
+			Affected{Ranges: []Range{{Type: RangeTypeSemver, Events: []Event{{Introduced: "1.0"}}}}},
+			"7.0", true, []string{},
+		},
+		{
+			"versions",
+			Affected{Versions: []string{"1.0", "1.1"}},
+			"1.1", true, []string{},
+		},
+		{
+			"git-ignored",
+			Affected{Ranges: []Range{{Type: RangeTypeGit, Events: []Event{{Introduced: "0"}}}}},
+			"1.0", false, []string{},
+		},
+		{
+			"invalid-version",
+			Affected{Ranges: []Range{{Type: RangeTypeSemver, Events: []Event{{Introduced: "1.0"}, {Fixed: "1.2"}}}}},
+			"abc", false, []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			affected, fixedIn := tc.affected.Affects(tc.version, compareDotted)
+			require.Equal(t, tc.expected, affected)
+			require.Equal(t, tc.fixedIn, fixedIn)
+		})
+	}
+}
+
+func TestPackageKey(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		pkg      Package
+		expected string
+	}{
+		{"purl", Package{Ecosystem: "npm", Name: "postcss", Purl: "pkg:npm/postcss"}, "pkg:npm/postcss"},
+		{"npm", Package{Ecosystem: "npm", Name: "braces"}, "pkg:npm/braces"},
+		{"npm-scoped", Package{Ecosystem: "npm", Name: "@babel/core"}, "pkg:npm/%40babel/core"},
+		{"go", Package{Ecosystem: "Go", Name: "golang.org/x/crypto"}, "pkg:golang/golang.org/x/crypto"},
+		{"maven", Package{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core"}, "pkg:maven/org.apache.logging.log4j/log4j-core"},
+		{"pypi", Package{Ecosystem: "PyPI", Name: "Jinja2"}, "pkg:pypi/jinja2"},
+		{"unknown-ecosystem", Package{Ecosystem: "Debian:12", Name: "openssl"}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.pkg.PackageKey())
+		})
+	}
+
+	require.Equal(t, "pkg:npm/braces", PurlKey("pkg:npm/braces@3.0.2?foo=bar#sub"))
+	require.Empty(t, PurlKey("not a purl"))
+}
+
+// zipDirectory writes the files in a directory to a zip file
+func zipDirectory(t *testing.T, dir, path string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	require.NoError(t, filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		zf, err := w.Create(filepath.Base(p))
+		if err != nil {
+			return err
+		}
+		_, err = zf.Write(data)
+		return err
+	}))
+	require.NoError(t, w.Close())
+}
+
+func TestLoad(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "osv.zip")
+	zipDirectory(t, "testdata/osv", zipPath)
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		vulns   int
+		mustErr bool
+	}{
+		{"directory", "testdata/osv", 4, false},
+		{"zip", zipPath, 4, false},
+		{"file", "testdata/osv/go/GO-2023-2402.json", 1, false},
+		{"missing", "testdata/nope", 0, true},
+		{"invalid", "osv.go", 0, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := Load(tc.path)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, db.Vulnerabilities, tc.vulns)
+		})
+	}
+
+	db, err := Load("testdata/osv")
+	require.NoError(t, err)
+	require.Len(t, db.Lookup("pkg:golang/golang.org/x/crypto@v0.11.0"), 1)
+	require.Len(t, db.Lookup("pkg:npm/braces@3.0.2"), 1)
+
+	// Withdrawn advisories are skipped
+	require.Empty(t, db.Lookup("pkg:npm/micromatch@4.0.2"))
+}
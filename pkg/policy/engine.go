@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/elements"
+	"github.com/protobom/cel/pkg/runner"
+)
+
+// Status is the outcome of evaluating a rule
+type Status string
+
+const (
+	// StatusPass means the rule condition evaluated to true
+	StatusPass Status = "pass"
+
+	// StatusFail means the rule condition evaluated to false
+	StatusFail Status = "fail"
+
+	// StatusError means the rule condition could not be evaluated
+	StatusError Status = "error"
+)
+
+// Violation is a node that failed a node scoped rule
+type Violation struct {
+	DocumentID string
+	NodeID     string
+	Name       string
+	Purl       string
+	Message    string
+}
+
+// RuleResult is the result of evaluating a rule
+type RuleResult struct {
+	Rule   Rule
+	Status Status
+
+	// Error is the CEL evaluation error when the status is StatusError
+	Error error
+
+	// Violations lists the nodes that failed a node scoped rule
+	Violations []Violation
+}
+
+// Report is the result of evaluating a policy
+type Report struct {
+	Policy  string
+	Results []RuleResult
+}
+
+// filter returns the results with a status
+func (r *Report) filter(status Status) []RuleResult {
+	ret := []RuleResult{}
+	for _, res := range r.Results {
+		if res.Status == status {
+			ret = append(ret, res)
+		}
+	}
+	return ret
+}
+
+// Passed returns the results of the rules that passed
+func (r *Report) Passed() []RuleResult {
+	return r.filter(StatusPass)
+}
+
+// Failed returns the results of the rules that failed
+func (r *Report) Failed() []RuleResult {
+	return r.filter(StatusFail)
+}
+
+// Errored returns the results of the rules that could not be evaluated
+func (r *Report) Errored() []RuleResult {
+	return r.filter(StatusError)
+}
+
+// OK returns true if all the rules in the report passed
+func (r *Report) OK() bool {
+	return len(r.Passed()) == len(r.Results)
+}
+
+// compiledRule is a rule with its condition ready to be evaluated
+type compiledRule struct {
+	Rule
+	query *runner.PreparedQuery
+
+	// docsVarName is the variable holding the SBOMs in the runner
+	// environment
+	docsVarName string
+
+	// nodes is the node selector of node scoped rules
+	nodes *runner.PreparedQuery
+}
+
+// Engine evaluates a policy. The rule conditions are compiled once when
+// the engine is created and can be evaluated many times.
+type Engine struct {
+	policy *Policy
+	rules  []compiledRule
+}
+
+// NewEngine compiles the policy rules in the CEL environment of the runner.
+// If the runner is nil, a new one is created with the default options. All
+// the rules that fail to compile are returned in the error.
+func NewEngine(p *Policy, r *runner.Runner) (*Engine, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+
+	if r == nil {
+		var err error
+		r, err = runner.NewRunner()
+		if err != nil {
+			return nil, fmt.Errorf("creating runner: %w", err)
+		}
+	}
+
+	e := &Engine{policy: p}
+	errs := []error{}
+	for _, rule := range p.Rules {
+		compiled, err := compileRule(r, rule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", rule.ID, err))
+			continue
+		}
+		e.rules = append(e.rules, *compiled)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// compileRule prepares the rule expressions and checks their types
+func compileRule(r *runner.Runner, rule Rule) (*compiledRule, error) {
+	ret := &compiledRule{Rule: rule, docsVarName: r.LibraryOptions().DocsVarName}
+	var err error
+
+	if rule.Scope != ScopeNode {
+		ret.query, err = r.Prepare(rule.Condition)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkOutputType(ret.query, cel.BoolType); err != nil {
+			return nil, fmt.Errorf("condition %w", err)
+		}
+		return ret, nil
+	}
+
+	ret.query, err = r.PrepareNodeQuery(rule.Condition)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOutputType(ret.query, cel.BoolType); err != nil {
+		return nil, fmt.Errorf("condition %w", err)
+	}
+
+	if rule.Nodes != "" {
+		ret.nodes, err = r.PrepareNodeQuery(rule.Nodes)
+		if err != nil {
+			return nil, fmt.Errorf("nodes: %w", err)
+		}
+		if err := checkOutputType(ret.nodes, elements.NodeListType); err != nil {
+			return nil, fmt.Errorf("nodes %w", err)
+		}
+	}
+	return ret, nil
+}
+
+// checkOutputType verifies a prepared query returns the expected type
+func checkOutputType(pq *runner.PreparedQuery, expected *cel.Type) error {
+	if t := pq.OutputType(); !t.IsExactType(expected) && !t.IsExactType(cel.DynType) {
+		return fmt.Errorf("must return %s, not %s", expected, t)
+	}
+	return nil
+}
+
+// Evaluate runs all the policy rules against the variables, usually built
+// with runner.BuildVariables, and returns the report.
+func (e *Engine) Evaluate(variables map[string]any) *Report {
+	report := &Report{
+		Policy:  e.policy.Name,
+		Results: make([]RuleResult, 0, len(e.rules)),
+	}
+
+	for _, rule := range e.rules {
+		report.Results = append(report.Results, rule.evaluate(variables))
+	}
+	return report
+}
+
+// evaluate runs the rule and returns its result
+func (rule *compiledRule) evaluate(variables map[string]any) RuleResult {
+	if rule.Scope == ScopeNode {
+		return rule.evaluateNodes(variables)
+	}
+
+	res := RuleResult{Rule: rule.Rule}
+
+	val, err := rule.query.Evaluate(variables)
+	if err != nil {
+		res.Status = StatusError
+		res.Error = err
+		return res
+	}
+
+	pass, ok := val.Value().(bool)
+	switch {
+	case !ok:
+		res.Status = StatusError
+		res.Error = fmt.Errorf("condition returned %s, not a bool", val.Type().TypeName())
+	case pass:
+		res.Status = StatusPass
+	default:
+		res.Status = StatusFail
+	}
+	return res
+}
+
+// evaluateNodes runs a node scoped rule on the nodes of all the SBOMs
+func (rule *compiledRule) evaluateNodes(variables map[string]any) RuleResult {
+	res := RuleResult{Rule: rule.Rule, Violations: []Violation{}}
+
+	docs, ok := variables[rule.docsVarName].([]*elements.Document)
+	if !ok {
+		res.Status = StatusError
+		res.Error = fmt.Errorf("variable %q does not hold a list of documents", rule.docsVarName)
+		return res
+	}
+
+	for _, doc := range docs {
+		var nl *sbom.NodeList
+		if rule.nodes != nil {
+			val, err := rule.nodes.Evaluate(runner.DocumentVariables(variables, doc.Document))
+			if err != nil {
+				res.Status = StatusError
+				res.Error = fmt.Errorf("selecting nodes: %w", err)
+				return res
+			}
+			if nl, ok = val.Value().(*sbom.NodeList); !ok {
+				res.Status = StatusError
+				res.Error = fmt.Errorf("nodes expression returned %s, not a NodeList", val.Type().TypeName())
+				return res
+			}
+		}
+
+		for _, nr := range runner.EvaluateNodes(rule.query, doc.Document, nl, variables) {
+			if nr.Error != nil {
+				res.Status = StatusError
+				res.Error = fmt.Errorf("node %q: %w", nr.NodeID, nr.Error)
+				return res
+			}
+			if nr.Passed {
+				continue
+			}
+			res.Violations = append(res.Violations, Violation{
+				DocumentID: doc.GetMetadata().GetId(),
+				NodeID:     nr.NodeID,
+				Name:       nr.Name,
+				Purl:       nr.Purl,
+				Message:    rule.Message,
+			})
+		}
+	}
+
+	res.Status = StatusPass
+	if len(res.Violations) > 0 {
+		res.Status = StatusFail
+	}
+	return res
+}
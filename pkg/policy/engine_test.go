@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/protobom/cel/pkg/library"
+	"github.com/protobom/cel/pkg/runner"
+)
+
+func testDocument() *sbom.Document {
+	doc := sbom.NewDocument()
+	doc.NodeList.AddRootNode(&sbom.Node{Id: "root", Name: "root", Type: sbom.Node_PACKAGE})
+	doc.NodeList.AddNode(&sbom.Node{Id: "file", Name: "file.tar.gz", Type: sbom.Node_FILE})
+	doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_contains, From: "root", To: []string{"file"}})
+	return doc
+}
+
+func TestEngine(t *testing.T) {
+	p, err := Load("testdata/policy.yaml")
+	require.NoError(t, err)
+	p.Rules = append(p.Rules,
+		Rule{ID: "out-of-range", Condition: "sboms[5].node_list.get_nodes().size() > 0", Severity: SeverityCritical, Message: "boom"},
+		Rule{ID: "not-bool", Condition: `sboms.size() > 0 ? dyn("yes") : dyn(false)`, Severity: SeverityInfo, Message: "not a bool"},
+	)
+
+	e, err := NewEngine(p, nil)
+	require.NoError(t, err)
+
+	vars, err := runner.BuildVariables(runner.WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	// The engine can be evaluated many times
+	for range 2 {
+		report := e.Evaluate(vars)
+		require.Equal(t, "baseline", report.Policy)
+		require.Len(t, report.Results, 5)
+		require.False(t, report.OK())
+
+		require.Len(t, report.Passed(), 2)
+		require.Equal(t, "has-root", report.Passed()[0].Rule.ID)
+		require.Equal(t, "has-files", report.Passed()[1].Rule.ID)
+
+		require.Len(t, report.Failed(), 1)
+		require.Equal(t, "no-tarballs", report.Failed()[0].Rule.ID)
+		require.Equal(t, "Tarballs are not allowed", report.Failed()[0].Rule.Message)
+		require.NoError(t, report.Failed()[0].Error)
+
+		require.Len(t, report.Errored(), 2)
+		require.Equal(t, "out-of-range", report.Errored()[0].Rule.ID)
+		require.ErrorContains(t, report.Errored()[0].Error, "5")
+		require.Equal(t, "not-bool", report.Errored()[1].Rule.ID)
+		require.Error(t, report.Errored()[1].Error)
+	}
+
+	// Evaluate against an SBOM without files
+	doc := sbom.NewDocument()
+	doc.NodeList.AddRootNode(&sbom.Node{Id: "root", Name: "root", Type: sbom.Node_PACKAGE})
+	vars, err = runner.BuildVariables(runner.WithDocuments([]*sbom.Document{doc}))
+	require.NoError(t, err)
+
+	report := e.Evaluate(vars)
+	require.Len(t, report.Failed(), 1)
+	require.Equal(t, "has-files", report.Failed()[0].Rule.ID)
+	require.Len(t, report.Passed(), 2)
+}
+
+func TestEngineNodeScope(t *testing.T) {
+	doc := testDocument()
+	doc.Metadata.Id = "test-sbom"
+	doc.NodeList.GetNodeByID("root").Suppliers = []*sbom.Person{{Name: "ACME"}}
+	doc.NodeList.GetNodeByID("root").Identifiers = map[int32]string{
+		int32(sbom.SoftwareIdentifierType_PURL): "pkg:generic/root@1.0",
+	}
+	vars, err := runner.BuildVariables(runner.WithDocuments([]*sbom.Document{doc}))
+	require.NoError(t, err)
+
+	e, err := NewEngine(&Policy{Rules: []Rule{
+		{
+			ID: "all-suppliers", Scope: ScopeNode, Severity: SeverityHigh,
+			Condition: "node.suppliers.size() > 0",
+			Message:   "Nodes must have a supplier",
+		},
+		{
+			ID: "package-suppliers", Scope: ScopeNode, Severity: SeverityHigh,
+			Condition: "node.suppliers.size() > 0",
+			Nodes:     "document.node_list.get_packages()",
+			Message:   "Packages must have a supplier",
+		},
+		{
+			ID: "leaves", Scope: ScopeNode, Severity: SeverityInfo,
+			Condition: "edges_from.size() == 0",
+			Message:   "Node has dependencies",
+		},
+		{
+			ID: "not-bool", Scope: ScopeNode, Severity: SeverityInfo,
+			Condition: "dyn(node.name)",
+		},
+	}}, nil)
+	require.NoError(t, err)
+
+	report := e.Evaluate(vars)
+	require.Len(t, report.Results, 4)
+
+	res := report.Results[0]
+	require.NoError(t, res.Error)
+	require.Equal(t, StatusFail, res.Status)
+	require.Equal(t, []Violation{{
+		DocumentID: "test-sbom", NodeID: "file", Name: "file.tar.gz", Message: "Nodes must have a supplier",
+	}}, res.Violations)
+
+	res = report.Results[1]
+	require.Equal(t, StatusPass, res.Status)
+	require.Empty(t, res.Violations)
+
+	res = report.Results[2]
+	require.Equal(t, StatusFail, res.Status)
+	require.Len(t, res.Violations, 1)
+	require.Equal(t, "root", res.Violations[0].NodeID)
+	require.Equal(t, "pkg:generic/root@1.0", res.Violations[0].Purl)
+
+	res = report.Results[3]
+	require.Equal(t, StatusError, res.Status)
+	require.ErrorContains(t, res.Error, "root")
+}
+
+func TestEngineDocsVarName(t *testing.T) {
+	libOpts := []library.OptFunc{library.WithDocsVarName("docs")}
+	r, err := runner.NewRunnerWithOptions(&runner.Options{LibraryOptions: libOpts})
+	require.NoError(t, err)
+	vars, err := runner.BuildVariables(
+		runner.WithDocuments([]*sbom.Document{testDocument()}),
+		runner.WithLibraryOptions(libOpts...),
+	)
+	require.NoError(t, err)
+
+	e, err := NewEngine(&Policy{Rules: []Rule{
+		{ID: "docs", Severity: SeverityInfo, Condition: "docs.size() == 1"},
+		{ID: "names", Scope: ScopeNode, Severity: SeverityInfo, Condition: "node.name != ''"},
+	}}, r)
+	require.NoError(t, err)
+
+	report := e.Evaluate(vars)
+	for _, res := range report.Results {
+		require.NoError(t, res.Error, res.Rule.ID)
+	}
+	require.True(t, report.OK())
+}
+
+func TestNewEngineErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy *Policy
+	}{
+		{"invalid-policy", &Policy{}},
+		{"syntax", &Policy{Rules: []Rule{{ID: "a", Condition: "sboms.all(", Severity: SeverityLow}}}},
+		{"unknown-function", &Policy{Rules: []Rule{{ID: "a", Condition: "sboms[0].nope()", Severity: SeverityLow}}}},
+		{"not-bool", &Policy{Rules: []Rule{{ID: "a", Condition: "sboms.size()", Severity: SeverityLow}}}},
+		{"node-in-document-scope", &Policy{Rules: []Rule{{ID: "a", Condition: "node.name == ''", Severity: SeverityLow}}}},
+		{"node-not-bool", &Policy{Rules: []Rule{{ID: "a", Condition: "node.name", Severity: SeverityLow, Scope: ScopeNode}}}},
+		{"nodes-not-nodelist", &Policy{Rules: []Rule{{ID: "a", Condition: "true", Severity: SeverityLow, Scope: ScopeNode, Nodes: "document"}}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewEngine(tc.policy, nil)
+			require.Error(t, err)
+		})
+	}
+
+	// All the failing rules are reported
+	_, err := NewEngine(&Policy{Rules: []Rule{
+		{ID: "first", Condition: "nope()", Severity: SeverityLow},
+		{ID: "second", Condition: "1", Severity: SeverityLow},
+	}}, nil)
+	require.ErrorContains(t, err, "first")
+	require.ErrorContains(t, err, "second")
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+// Package policy implements an engine to evaluate sets of named CEL rules
+// against SBOMs and report on the results.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Severity is the importance of a rule violation
+type Severity string
+
+// Rule severities, from least to most important
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Severities lists the valid severities ordered by importance
+var Severities = []Severity{
+	SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical,
+}
+
+// Scope defines what a rule condition is evaluated against
+type Scope string
+
+const (
+	// ScopeDocument rules are evaluated once with the SBOMs loaded in the
+	// environment. This is the default.
+	ScopeDocument Scope = "document"
+
+	// ScopeNode rules are evaluated once for each node of every SBOM with
+	// the `node`, `document` and `edges_from` variables bound.
+	ScopeNode Scope = "node"
+)
+
+// Rule is a named CEL condition. The rule passes when the condition
+// evaluates to true.
+type Rule struct {
+	// ID is the unique identifier of the rule in the policy
+	ID string `json:"id" yaml:"id"`
+
+	// Condition is a CEL expression returning a boolean
+	Condition string `json:"condition" yaml:"condition"`
+
+	// Severity is the importance of the rule when it fails
+	Severity Severity `json:"severity" yaml:"severity"`
+
+	// Message describes the problem when the rule fails
+	Message string `json:"message" yaml:"message"`
+
+	// Scope sets if the condition is evaluated once or once per node
+	Scope Scope `json:"scope,omitempty" yaml:"scope,omitempty"`
+
+	// Nodes is an expression returning the NodeList to evaluate in node
+	// scoped rules. It is evaluated once per SBOM with `document` bound,
+	// if empty, all the nodes in the SBOM are evaluated.
+	Nodes string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+}
+
+// Policy is a named set of rules
+type Policy struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Load reads a policy from a YAML or JSON file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy file: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("loading %q: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse parses a policy from YAML or JSON data and validates it
+func Parse(data []byte) (*Policy, error) {
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("parsing policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks the policy rules are complete and their IDs are unique
+func (p *Policy) Validate() error {
+	if len(p.Rules) == 0 {
+		return errors.New("policy has no rules")
+	}
+
+	errs := []error{}
+	ids := map[string]struct{}{}
+	for i, r := range p.Rules {
+		if r.ID == "" {
+			errs = append(errs, fmt.Errorf("rule #%d has no id", i))
+		} else if _, ok := ids[r.ID]; ok {
+			errs = append(errs, fmt.Errorf("duplicate rule id %q", r.ID))
+		}
+		ids[r.ID] = struct{}{}
+
+		if r.Condition == "" {
+			errs = append(errs, fmt.Errorf("rule %q has no condition", r.ID))
+		}
+		if !slices.Contains(Severities, r.Severity) {
+			errs = append(errs, fmt.Errorf("rule %q has invalid severity %q", r.ID, r.Severity))
+		}
+		switch r.Scope {
+		case "", ScopeDocument:
+			if r.Nodes != "" {
+				errs = append(errs, fmt.Errorf("rule %q: nodes can only be set in node scoped rules", r.ID))
+			}
+		case ScopeNode:
+		default:
+			errs = append(errs, fmt.Errorf("rule %q has invalid scope %q", r.ID, r.Scope))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	for _, tc := range []struct {
+		path    string
+		rules   int
+		mustErr bool
+	}{
+		{"testdata/policy.yaml", 3, false},
+		{"testdata/policy.json", 1, false},
+		{"testdata/missing.yaml", 0, true},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := Load(tc.path)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "baseline", p.Name)
+			require.Len(t, p.Rules, tc.rules)
+			require.Equal(t, "has-root", p.Rules[0].ID)
+			require.Equal(t, SeverityHigh, p.Rules[0].Severity)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		data    string
+		mustErr bool
+	}{
+		{"valid", "rules: [{id: a, condition: 'true', severity: info, message: m}]", false},
+		{"no-rules", "name: empty", true},
+		{"no-id", "rules: [{condition: 'true', severity: info}]", true},
+		{"duplicate-id", "rules: [{id: a, condition: 'true', severity: low}, {id: a, condition: 'false', severity: low}]", true},
+		{"no-condition", "rules: [{id: a, severity: low}]", true},
+		{"bad-severity", "rules: [{id: a, condition: 'true', severity: urgent}]", true},
+		{"no-severity", "rules: [{id: a, condition: 'true'}]", true},
+		{"node-scope", "rules: [{id: a, condition: 'node.name != \"\"', severity: low, scope: node, nodes: 'document.node_list'}]", false},
+		{"bad-scope", "rules: [{id: a, condition: 'true', severity: low, scope: edge}]", true},
+		{"nodes-in-document-scope", "rules: [{id: a, condition: 'true', severity: low, nodes: 'document.node_list'}]", true},
+		{"invalid-yaml", "rules: [", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"container/list"
+	"sync"
+)
+
+// programCache is a bounded least-recently-used cache of prepared queries
+// keyed by the expression source code. It is safe for concurrent use.
+type programCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// cacheEntry is the value stored in the cache list elements
+type cacheEntry struct {
+	code  string
+	query *PreparedQuery
+}
+
+// newProgramCache creates a cache holding up to size queries.
+func newProgramCache(size int) *programCache {
+	return &programCache{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Get returns the query cached for code, marking it as recently used.
+func (c *programCache) Get(code string) (*PreparedQuery, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[code]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).query, true //nolint:forcetypeassert // The list only stores entries
+}
+
+// Add stores a query in the cache, evicting the least recently used
+// entry if the cache is full.
+func (c *programCache) Add(code string, query *PreparedQuery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[code]; ok {
+		el.Value.(*cacheEntry).query = query //nolint:forcetypeassert // The list only stores entries
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[code] = c.order.PushFront(&cacheEntry{code: code, query: query})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).code) //nolint:forcetypeassert // The list only stores entries
+	}
+}
+
+// Len returns the number of queries in the cache
+func (c *programCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,421 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/parser"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/library"
+)
+
+// maxExplainValueLen is the length at which values are truncated in the
+// explain listing
+const maxExplainValueLen = 60
+
+// Explanation captures the evaluation of an expression step by step
+type Explanation struct {
+	// Code is the explained expression
+	Code string
+
+	// Result is the value of the expression, nil if it failed
+	Result ref.Val
+
+	// Error is the evaluation error, if any
+	Error error
+
+	// Steps are the subexpressions in the order they appear in the syntax
+	// tree, starting with the whole expression.
+	Steps []ExplainStep
+}
+
+// ExplainStep is a subexpression and the value it evaluated to
+type ExplainStep struct {
+	// ID is the expression ID in the CEL syntax tree
+	ID int64
+
+	// Depth is the nesting level of the subexpression in the syntax tree
+	Depth int
+
+	// Expression is the source of the subexpression
+	Expression string
+
+	// Start and End are the offsets of the subexpression in the code,
+	// counted in unicode code points.
+	Start int
+	End   int
+
+	// Line and Column are the position where the subexpression starts,
+	// both starting at 1.
+	Line   int
+	Column int
+
+	// Evaluated is false when the subexpression was not evaluated, for
+	// example on the short-circuited side of a logical operator.
+	Evaluated bool
+
+	// Value is the result of the subexpression
+	Value ref.Val
+
+	// Call is set when the subexpression calls a protobom function
+	Call *ExplainCall
+}
+
+// ExplainCall reports the nodes flowing through a protobom function call
+type ExplainCall struct {
+	Function string
+
+	// NodesIn is the number of nodes in the arguments, including the
+	// receiver of member functions.
+	NodesIn int
+
+	// NodesOut is the number of nodes in the result
+	NodesOut int
+}
+
+// protobomFunctions returns the names of the functions declared by the
+// protobom library.
+var protobomFunctions = sync.OnceValue(func() map[string]struct{} {
+	ret := map[string]struct{}{}
+	env, err := cel.NewCustomEnv(library.NewProtobom(library.WithEnableIO(true)).EnvOption())
+	if err != nil {
+		return ret
+	}
+	for name := range env.Functions() {
+		// Skip the operators overloaded by the library
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		ret[name] = struct{}{}
+	}
+	return ret
+})
+
+// explainEnvironment returns the runner environment with macro call
+// tracking enabled, required to print the subexpressions that contain
+// macros. It is created once, on first use.
+func (r *Runner) explainEnvironment() (*cel.Env, error) {
+	r.explainEnvOnce.Do(func() {
+		r.explainEnv, r.explainEnvErr = r.Environment.Extend(cel.EnableMacroCallTracking())
+	})
+	if r.explainEnvErr != nil {
+		return nil, fmt.Errorf("creating explain environment: %w", r.explainEnvErr)
+	}
+	return r.explainEnv, nil
+}
+
+// Explain evaluates code tracking the value of each subexpression. Errors
+// compiling the expression are returned, evaluation errors are recorded in
+// the explanation. Explain honors the runner limits and stops when the
+// context is canceled.
+func (r *Runner) Explain(ctx context.Context, code string, variables map[string]any) (*Explanation, error) {
+	env, err := r.explainEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	checked, iss := env.Compile(code)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("compilation error: %w", iss.Err())
+	}
+
+	opts := append([]cel.ProgramOption{cel.EvalOptions(cel.OptTrackState)}, r.programOptions...)
+	program, err := env.Program(checked, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("planning program: %w", err)
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	ret := &Explanation{Code: code}
+	var details *cel.EvalDetails
+	ret.Result, details, ret.Error = program.ContextEval(ctx, variables)
+	if ret.Error != nil {
+		ret.Result = nil
+	}
+
+	var state interface {
+		Value(int64) (ref.Val, bool)
+	}
+	if details != nil && details.State() != nil {
+		state = details.State()
+	}
+
+	native := checked.NativeRep()
+	w := &explainWalker{
+		code:      []rune(code),
+		info:      native.SourceInfo(),
+		state:     state,
+		variables: variables,
+		adapter:   env.CELTypeAdapter(),
+		functions: protobomFunctions(),
+	}
+	w.walk(native.Expr(), 0, true)
+	ret.Steps = w.steps
+	return ret, nil
+}
+
+// explainWalker collects the steps of an explanation from the syntax tree
+type explainWalker struct {
+	code  []rune
+	info  *ast.SourceInfo
+	state interface {
+		Value(int64) (ref.Val, bool)
+	}
+	variables map[string]any
+	adapter   types.Adapter
+	functions map[string]struct{}
+	steps     []ExplainStep
+}
+
+// children returns the subexpressions of an expression. Only the range of
+// comprehensions is visited as their loop internals are not part of the
+// source code. Comprehensions that only bind a variable (cel.bind() and the
+// where() macro) never iterate, their initializer is visited instead.
+func children(e ast.Expr) []ast.Expr {
+	switch e.Kind() {
+	case ast.CallKind:
+		c := e.AsCall()
+		ret := []ast.Expr{}
+		if c.IsMemberFunction() {
+			ret = append(ret, c.Target())
+		}
+		return append(ret, c.Args()...)
+	case ast.SelectKind:
+		return []ast.Expr{e.AsSelect().Operand()}
+	case ast.ListKind:
+		return e.AsList().Elements()
+	case ast.MapKind:
+		ret := []ast.Expr{}
+		for _, entry := range e.AsMap().Entries() {
+			ret = append(ret, entry.AsMapEntry().Key(), entry.AsMapEntry().Value())
+		}
+		return ret
+	case ast.StructKind:
+		ret := []ast.Expr{}
+		for _, f := range e.AsStruct().Fields() {
+			ret = append(ret, f.AsStructField().Value())
+		}
+		return ret
+	case ast.ComprehensionKind:
+		comp := e.AsComprehension()
+		if r := comp.IterRange(); r.Kind() == ast.ListKind && len(r.AsList().Elements()) == 0 {
+			return []ast.Expr{comp.AccuInit()}
+		}
+		return []ast.Expr{comp.IterRange()}
+	default:
+		return nil
+	}
+}
+
+// span returns the range of source offsets covered by an expression
+func (w *explainWalker) span(e ast.Expr) (start, end int) {
+	start, end = -1, -1
+	if r, ok := w.info.GetOffsetRange(e.ID()); ok {
+		start, end = int(r.Start), int(r.Stop)
+	}
+	// Comprehensions expanded from macros span the whole macro call
+	nodes := children(e)
+	closing := rune(0)
+	if call, ok := w.info.GetMacroCall(e.ID()); ok {
+		nodes = children(call)
+		closing = ')'
+	} else if e.Kind() == ast.CallKind {
+		switch fn := e.AsCall().FunctionName(); {
+		case fn == operators.Index || fn == operators.OptIndex:
+			closing = ']'
+		case !strings.HasPrefix(fn, "_") && !strings.HasPrefix(fn, "@"):
+			closing = ')'
+		}
+	}
+	for _, c := range nodes {
+		cs, ce := w.span(c)
+		if cs >= 0 && (start < 0 || cs < start) {
+			start = cs
+		}
+		if ce > end {
+			end = ce
+		}
+	}
+
+	// The offsets of calls don't include their closing bracket
+	if closing != 0 && end >= 0 {
+		for i := end; i < len(w.code); i++ {
+			if w.code[i] == closing {
+				end = i + 1
+				break
+			}
+			if !unicode.IsSpace(w.code[i]) {
+				break
+			}
+		}
+	}
+	return start, end
+}
+
+// walk adds the steps of an expression and its children in pre-order
+func (w *explainWalker) walk(e ast.Expr, depth int, parentEvaluated bool) {
+	step := ExplainStep{ID: e.ID(), Depth: depth}
+	step.Start, step.End = w.span(e)
+	if step.Start >= 0 {
+		loc := w.info.GetLocationByOffset(int32(step.Start))
+		step.Line, step.Column = loc.Line(), loc.Column()+1
+	}
+
+	if src, err := parser.Unparse(e, w.info); err == nil {
+		step.Expression = src
+	} else if step.Start >= 0 && step.End <= len(w.code) {
+		step.Expression = string(w.code[step.Start:step.End])
+	}
+
+	if w.state != nil {
+		step.Value, step.Evaluated = w.state.Value(e.ID())
+	}
+
+	// Literals and variables folded into their parent expression are not
+	// tracked, read their values from the syntax tree and the variables.
+	if !step.Evaluated && parentEvaluated && w.state != nil {
+		switch e.Kind() {
+		case ast.LiteralKind:
+			step.Value, step.Evaluated = e.AsLiteral(), true
+		case ast.IdentKind:
+			if v, ok := w.variables[e.AsIdent()]; ok {
+				step.Value, step.Evaluated = w.adapter.NativeToValue(v), true
+			}
+		}
+	}
+
+	if e.Kind() == ast.CallKind && step.Evaluated {
+		if _, ok := w.functions[e.AsCall().FunctionName()]; ok {
+			step.Call = &ExplainCall{
+				Function: e.AsCall().FunctionName(),
+				NodesOut: countNodes(step.Value),
+			}
+			for _, arg := range children(e) {
+				if v, ok := w.state.Value(arg.ID()); ok {
+					step.Call.NodesIn += countNodes(v)
+				}
+			}
+		}
+	}
+
+	w.steps = append(w.steps, step)
+	for _, c := range children(e) {
+		w.walk(c, depth+1, step.Evaluated)
+	}
+}
+
+// countNodes returns the number of nodes held in a value
+func countNodes(val ref.Val) int {
+	if val == nil {
+		return 0
+	}
+	switch v := val.Value().(type) {
+	case *sbom.Document:
+		return len(v.GetNodeList().GetNodes())
+	case *sbom.NodeList:
+		return len(v.GetNodes())
+	case *sbom.Node:
+		return 1
+	}
+
+	lister, ok := val.(traits.Lister)
+	if !ok {
+		return 0
+	}
+	count := 0
+	it := lister.Iterator()
+	for it.HasNext() == types.True {
+		count += countNodes(it.Next())
+	}
+	return count
+}
+
+// formatValue returns a short description of a value for the listing
+func formatValue(val ref.Val) string {
+	if val == nil {
+		return "<nil>"
+	}
+
+	var s string
+	switch v := val.Value().(type) {
+	case *sbom.Document:
+		s = fmt.Sprintf("Document(%q, %d nodes)", v.GetMetadata().GetId(), len(v.GetNodeList().GetNodes()))
+	case *sbom.NodeList:
+		s = fmt.Sprintf("NodeList(%d nodes)", len(v.GetNodes()))
+	case *sbom.Node:
+		s = fmt.Sprintf("Node(%q)", v.GetId())
+	case string:
+		s = fmt.Sprintf("%q", v)
+	default:
+		if types.IsError(val) {
+			s = fmt.Sprintf("error: %v", val)
+		} else if l, ok := val.(traits.Sizer); ok {
+			s = fmt.Sprintf("%s(%v items)", val.Type().TypeName(), l.Size())
+		} else {
+			s = fmt.Sprintf("%v", val.Value())
+		}
+	}
+
+	if utf8.RuneCountInString(s) > maxExplainValueLen {
+		s = string([]rune(s)[:maxExplainValueLen-3]) + "..."
+	}
+	return s
+}
+
+// WriteListing writes the explanation as an annotated listing, one line per
+// subexpression indented by its depth in the syntax tree:
+//
+//	1:1   sboms[0].get_files().get_nodes().size() > 0 => true
+//	1:1     sboms[0].get_files().get_nodes().size() => 3
+//	1:1       sboms[0].get_files().get_nodes() => list(3 items) [get_nodes: 3 nodes in, 3 out]
+func (e *Explanation) WriteListing(w io.Writer) error {
+	var b strings.Builder
+	for _, s := range e.Steps {
+		value := "(not evaluated)"
+		if s.Evaluated {
+			value = formatValue(s.Value)
+		}
+		fmt.Fprintf(&b, "%-5s %s%s => %s", fmt.Sprintf("%d:%d", s.Line, s.Column), strings.Repeat("  ", s.Depth), s.Expression, value)
+		if s.Call != nil {
+			fmt.Fprintf(&b, " [%s: %d nodes in, %d out]", s.Call.Function, s.Call.NodesIn, s.Call.NodesOut)
+		}
+		b.WriteString("\n")
+	}
+
+	if e.Error != nil {
+		fmt.Fprintf(&b, "error: %v\n", e.Error)
+	} else {
+		fmt.Fprintf(&b, "result: %s\n", formatValue(e.Result))
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing listing: %w", err)
+	}
+	return nil
+}
+
+// String returns the annotated listing of the explanation
+func (e *Explanation) String() string {
+	var b strings.Builder
+	if err := e.WriteListing(&b); err != nil {
+		return err.Error()
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	r, err := NewRunner()
+	require.NoError(t, err)
+
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	code := "sboms[0].get_files().get_nodes().size() > 0 &&\n  sboms[0].node_list.get_nodes().exists(n, n.name == 'root')"
+	e, err := r.Explain(context.Background(), code, vars)
+	require.NoError(t, err)
+	require.NoError(t, e.Error)
+	require.Equal(t, true, e.Result.Value())
+
+	steps := map[string]ExplainStep{}
+	for _, s := range e.Steps {
+		steps[s.Expression] = s
+	}
+
+	// The whole expression is the first step
+	require.Equal(t, 0, e.Steps[0].Depth)
+	require.Equal(t, 1, e.Steps[0].Line)
+	require.Equal(t, 1, e.Steps[0].Column)
+	require.Equal(t, true, e.Steps[0].Value.Value())
+
+	s, ok := steps["sboms[0].get_files().get_nodes().size()"]
+	require.True(t, ok)
+	require.Equal(t, int64(1), s.Value.Value())
+	require.Nil(t, s.Call)
+
+	s, ok = steps["sboms[0].get_files()"]
+	require.True(t, ok)
+	require.NotNil(t, s.Call)
+	require.Equal(t, "get_files", s.Call.Function)
+	require.Equal(t, 2, s.Call.NodesIn)
+	require.Equal(t, 1, s.Call.NodesOut)
+
+	// Subexpressions are mapped to their position in the source
+	s, ok = steps["sboms[0].node_list.get_nodes().exists(n, n.name == \"root\")"]
+	require.True(t, ok)
+	require.Equal(t, 2, s.Line)
+	require.Equal(t, 3, s.Column)
+	require.Equal(t, "sboms[0].node_list.get_nodes().exists(n, n.name == 'root')", code[s.Start:s.End])
+
+	s, ok = steps["sboms[0].node_list.get_nodes()"]
+	require.True(t, ok)
+	require.Equal(t, 2, s.Call.NodesIn)
+	require.Equal(t, 2, s.Call.NodesOut)
+
+	listing := e.String()
+	require.Contains(t, listing, "[get_files: 2 nodes in, 1 out]")
+	require.Contains(t, listing, "result: true")
+
+	// Short-circuited branches are not evaluated
+	e, err = r.Explain(context.Background(), "false && sboms[0].get_files().get_nodes().size() > 0", vars)
+	require.NoError(t, err)
+	require.False(t, e.Steps[len(e.Steps)-1].Evaluated)
+	require.True(t, strings.Contains(e.String(), "(not evaluated)"))
+
+	// Evaluation errors are part of the explanation
+	e, err = r.Explain(context.Background(), "sboms[3].get_files()", vars)
+	require.NoError(t, err)
+	require.Error(t, e.Error)
+	require.Contains(t, e.String(), "error:")
+
+	_, err = r.Explain(context.Background(), "sboms[0].nope()", vars)
+	require.Error(t, err)
+}
+
+func TestFormatValue(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		val      ref.Val
+		expected string
+	}{
+		{"nil", nil, "<nil>"},
+		{"string", types.String("hello"), `"hello"`},
+		{"int", types.Int(42), "42"},
+		{"truncated", types.String(strings.Repeat("a", 100)), `"` + strings.Repeat("a", 56) + "..."},
+		{"truncated-multibyte", types.String(strings.Repeat("ñ", 100)), `"` + strings.Repeat("ñ", 56) + "..."},
+		{"multibyte-fits", types.String(strings.Repeat("ñ", 58)), `"` + strings.Repeat("ñ", 58) + `"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := formatValue(tc.val)
+			require.Equal(t, tc.expected, s)
+			require.True(t, utf8.ValidString(s))
+		})
+	}
+}
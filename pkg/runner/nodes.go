@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/protobom/cel/pkg/adapter"
+	"github.com/protobom/cel/pkg/elements"
+)
+
+// Names of the variables bound when evaluating node queries
+const (
+	// NodeVarName holds the node being evaluated
+	NodeVarName = "node"
+
+	// DocumentVarName holds the document the node belongs to
+	DocumentVarName = "document"
+
+	// EdgesFromVarName holds the edges in the document going out of the node
+	EdgesFromVarName = "edges_from"
+)
+
+// NodeResult is the result of evaluating a node query on a single node
+type NodeResult struct {
+	NodeID string
+	Name   string
+	Purl   string
+
+	// Passed is true when the expression evaluated to true
+	Passed bool
+
+	// Error is set when the expression could not be evaluated or did not
+	// return a boolean
+	Error error
+}
+
+// nodeEnvironment returns the runner environment extended with the node
+// query variables. It is created once, on first use.
+func (r *Runner) nodeEnvironment() (*cel.Env, error) {
+	r.nodeEnvOnce.Do(func() {
+		r.nodeEnv, r.nodeEnvErr = r.Environment.Extend(
+			cel.Variable(NodeVarName, elements.NodeType),
+			cel.Variable(DocumentVarName, elements.DocumentType),
+			cel.Variable(EdgesFromVarName, cel.ListType(elements.EdgeType)),
+		)
+	})
+	if r.nodeEnvErr != nil {
+		return nil, fmt.Errorf("creating node environment: %w", r.nodeEnvErr)
+	}
+	return r.nodeEnv, nil
+}
+
+// PrepareNodeQuery compiles an expression to be evaluated once per node
+// with EvaluateNodes. In addition to the regular variables, the expression
+// can reference `node`, `document` and `edges_from`:
+//
+//	node.suppliers.size() > 0 || edges_from.size() > 0
+//
+// Node queries are not stored in the runner cache.
+func (r *Runner) PrepareNodeQuery(code string) (*PreparedQuery, error) {
+	env, err := r.nodeEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return r.prepare(env, code)
+}
+
+// DocumentVariables returns a copy of variables with the document bound
+// to the `document` variable of node queries.
+func DocumentVariables(variables map[string]any, doc *sbom.Document) map[string]any {
+	ret := maps.Clone(variables)
+	if ret == nil {
+		ret = map[string]any{}
+	}
+	ret[DocumentVarName] = &elements.Document{Document: doc}
+	return ret
+}
+
+// EvaluateNodes runs a query prepared with PrepareNodeQuery once for each
+// node in nl, binding the node, the document and the edges going out of the
+// node in the document. If nl is nil, all the nodes in the document are
+// evaluated. The query must return a boolean.
+func EvaluateNodes(pq *PreparedQuery, doc *sbom.Document, nl *sbom.NodeList, variables map[string]any) []NodeResult {
+	if nl == nil {
+		nl = doc.GetNodeList()
+	}
+
+	// Index the outgoing edges of the document nodes
+	edgesFrom := map[string][]ref.Val{}
+	for _, e := range doc.GetNodeList().GetEdges() {
+		edgesFrom[e.GetFrom()] = append(edgesFrom[e.GetFrom()], &elements.Edge{Edge: e})
+	}
+
+	vars := DocumentVariables(variables, doc)
+	results := make([]NodeResult, 0, len(nl.GetNodes()))
+	for _, n := range nl.GetNodes() {
+		vars[NodeVarName] = &elements.Node{Node: n}
+		vars[EdgesFromVarName] = types.NewRefValList(adapter.ProtobomTypeAdapter{}, edgesFrom[n.GetId()])
+
+		res := NodeResult{
+			NodeID: n.GetId(),
+			Name:   n.GetName(),
+			Purl:   string(n.Purl()),
+		}
+
+		val, err := pq.Evaluate(vars)
+		if err != nil {
+			res.Error = err
+			results = append(results, res)
+			continue
+		}
+
+		passed, ok := val.Value().(bool)
+		if !ok {
+			res.Error = fmt.Errorf("expression returned %s, not a bool", val.Type().TypeName())
+		}
+		res.Passed = passed
+		results = append(results, res)
+	}
+	return results
+}
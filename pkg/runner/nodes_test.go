@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateNodes(t *testing.T) {
+	r, err := NewRunner()
+	require.NoError(t, err)
+
+	doc := testDocument()
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{doc}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		code     string
+		nl       *sbom.NodeList
+		expected map[string]bool
+		mustErr  bool
+	}{
+		{"node", `node.name == "root"`, nil, map[string]bool{"root": true, "file": false}, false},
+		{"edges-from", "edges_from.size() > 0", nil, map[string]bool{"root": true, "file": false}, false},
+		{"document", "document.node_list.get_nodes().size() == 2", nil, map[string]bool{"root": true, "file": true}, false},
+		{"variables", "sboms.size() == 1", nil, map[string]bool{"root": true, "file": true}, false},
+		{"node-list", "edges_from.size() == 0", &sbom.NodeList{Nodes: []*sbom.Node{doc.GetNodeList().GetNodeByID("file")}}, map[string]bool{"file": true}, false},
+		{"not-bool", `dyn(node.name)`, nil, nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pq, err := r.PrepareNodeQuery(tc.code)
+			require.NoError(t, err)
+
+			results := EvaluateNodes(pq, doc, tc.nl, vars)
+			if tc.mustErr {
+				require.NotEmpty(t, results)
+				for _, res := range results {
+					require.Error(t, res.Error)
+				}
+				return
+			}
+
+			require.Len(t, results, len(tc.expected))
+			for _, res := range results {
+				require.NoError(t, res.Error)
+				require.Equal(t, tc.expected[res.NodeID], res.Passed, res.NodeID)
+			}
+		})
+	}
+
+	// Node variables are not available in regular queries
+	_, err = r.Prepare("node.name == ''")
+	require.Error(t, err)
+
+	_, err = r.PrepareNodeQuery("node.does_not_exist()")
+	require.Error(t, err)
+}
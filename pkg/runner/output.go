@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/mod"
+	"github.com/protobom/protobom/pkg/native"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/protobom/protobom/pkg/writer"
+)
+
+// SupportedFormats are the SBOM formats that WriteDocument can serialize
+// evaluation results to.
+var SupportedFormats = []formats.Format{
+	formats.SPDX23JSON,
+	formats.CDX14JSON,
+	formats.CDX15JSON,
+	formats.CDX16JSON,
+}
+
+// documentIndent is the number of spaces used to indent the JSON documents
+const documentIndent = 2
+
+// formatNames maps the short format names to their full format
+var formatNames = map[string]formats.Format{
+	"spdx":          formats.SPDX23JSON,
+	"spdx-2.3":      formats.SPDX23JSON,
+	"cyclonedx":     formats.CDX16JSON,
+	"cyclonedx-1.4": formats.CDX14JSON,
+	"cyclonedx-1.5": formats.CDX15JSON,
+	"cyclonedx-1.6": formats.CDX16JSON,
+	"cdx":           formats.CDX16JSON,
+	"cdx-1.4":       formats.CDX14JSON,
+	"cdx-1.5":       formats.CDX15JSON,
+	"cdx-1.6":       formats.CDX16JSON,
+}
+
+// FormatFromString returns the SBOM format matching a short name such as
+// "spdx", "cyclonedx" or "cyclonedx-1.5". Full format strings (for example
+// "text/spdx+json;version=2.3") are also accepted. Unversioned names resolve
+// to the latest supported version.
+func FormatFromString(name string) (formats.Format, error) {
+	if f, ok := formatNames[strings.ToLower(name)]; ok {
+		return f, nil
+	}
+
+	if slices.Contains(SupportedFormats, formats.Format(name)) {
+		return formats.Format(name), nil
+	}
+
+	return "", fmt.Errorf("unsupported SBOM format %q", name)
+}
+
+// WriteDocument serializes an evaluation result to w as a native SBOM in the
+// specified format. The result must be a Document, expressions returning
+// other elements can be converted using to_document().
+func WriteDocument(w io.Writer, result ref.Val, format formats.Format) error {
+	if result == nil {
+		return errors.New("unable to write document, result is nil")
+	}
+
+	if types.IsError(result) {
+		return fmt.Errorf("unable to write document, evaluation returned an error: %v", result)
+	}
+
+	if !slices.Contains(SupportedFormats, format) {
+		return fmt.Errorf("unsupported SBOM format %q", format)
+	}
+
+	doc, ok := result.Value().(*sbom.Document)
+	if !ok {
+		switch result.Value().(type) {
+		case *sbom.NodeList, *sbom.Node:
+			return fmt.Errorf(
+				"result is a %s, not a document (convert it with to_document())",
+				result.Type().TypeName(),
+			)
+		default:
+			return fmt.Errorf("result of type %s cannot be written as a document", result.Type().TypeName())
+		}
+	}
+
+	// The options are built here instead of using the writer functional
+	// options as those modify the writer package defaults. Documents built
+	// from nodelists often have more than one root, so CycloneDX documents
+	// are written headless to be able to represent them. The CycloneDX
+	// serializer ignores the indent and always uses two spaces, SPDX
+	// documents are indented the same way to get consistent output.
+	opts := &writer.Options{
+		Format:        format,
+		RenderOptions: &native.RenderOptions{Indent: documentIndent},
+		SerializeOptions: &native.SerializeOptions{
+			Mods: map[mod.Mod]struct{}{
+				mod.CYCLONEDX_MULTIROOT_HEADLESS: {},
+			},
+		},
+	}
+
+	if err := writer.New().WriteStreamWithOptions(doc, w, opts); err != nil {
+		return fmt.Errorf("writing document: %w", err)
+	}
+	return nil
+}
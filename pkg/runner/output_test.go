@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFromString(t *testing.T) {
+	for _, tc := range []struct {
+		sut      string
+		expected formats.Format
+		mustErr  bool
+	}{
+		{"spdx", formats.SPDX23JSON, false},
+		{"SPDX-2.3", formats.SPDX23JSON, false},
+		{"cyclonedx", formats.CDX16JSON, false},
+		{"cyclonedx-1.4", formats.CDX14JSON, false},
+		{"cdx-1.5", formats.CDX15JSON, false},
+		{string(formats.CDX16JSON), formats.CDX16JSON, false},
+		{"spdx-2.2", "", true},
+		{"cyclonedx-1.2", "", true},
+		{string(formats.SPDX23TV), "", true},
+	} {
+		t.Run(tc.sut, func(t *testing.T) {
+			f, err := FormatFromString(tc.sut)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, f)
+		})
+	}
+}
+
+func TestWriteDocument(t *testing.T) {
+	r, err := NewRunner()
+	require.NoError(t, err)
+
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		code    string
+		format  formats.Format
+		mustErr bool
+		key     string
+	}{
+		{"spdx", "sboms[0].get_packages().to_document()", formats.SPDX23JSON, false, "spdxVersion"},
+		{"cdx-1.4", "sboms[0].get_packages().to_document()", formats.CDX14JSON, false, "specVersion"},
+		{"cdx-1.5", "sboms[0].to_document()", formats.CDX15JSON, false, "specVersion"},
+		{"cdx-1.6-multiroot", "sboms[0].get_files().add(sboms[0].get_packages()).to_document()", formats.CDX16JSON, false, "specVersion"},
+		{"nodelist", "sboms[0].get_packages()", formats.SPDX23JSON, true, ""},
+		{"string", "sboms[0].metadata.name", formats.SPDX23JSON, true, ""},
+		{"unsupported-format", "sboms[0].to_document()", formats.SPDX22JSON, true, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := r.Evaluate(tc.code, vars)
+			require.NoError(t, err)
+
+			var b bytes.Buffer
+			err = WriteDocument(&b, res, tc.format)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			data := map[string]any{}
+			require.NoError(t, json.Unmarshal(b.Bytes(), &data))
+			require.Contains(t, data, tc.key)
+
+			// All the formats are indented with two spaces
+			lines := strings.Split(b.String(), "\n")
+			require.Greater(t, len(lines), 2)
+			require.Equal(t, "{", lines[0])
+			require.True(t, strings.HasPrefix(lines[1], "  \""), lines[1])
+		})
+	}
+
+	require.Error(t, WriteDocument(&bytes.Buffer{}, nil, formats.SPDX23JSON))
+	require.Error(t, WriteDocument(&bytes.Buffer{}, types.NewErr("boom"), formats.SPDX23JSON))
+}
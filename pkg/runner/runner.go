@@ -4,7 +4,13 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types/ref"
@@ -18,8 +24,30 @@ import (
 
 type Options struct {
 	EnvOptions []cel.EnvOption
+
+	// LibraryOptions are passed to the protobom library when creating
+	// the CEL environment, for example to enable the I/O functions.
+	LibraryOptions []library.OptFunc
+
+	// CacheSize is the number of prepared queries the runner keeps in its
+	// cache, keyed by the expression text. Repeated evaluations of a cached
+	// expression skip parsing, checking and planning. Zero disables the cache.
+	CacheSize int
+
+	// CostLimit is the maximum actual cost an evaluation can reach before
+	// it is aborted. Protobom functions cost one unit per node and edge in
+	// the SBOM graphs they receive. Zero means no limit.
+	CostLimit uint64
+
+	// Timeout is the maximum time an evaluation can run. Zero means no
+	// timeout.
+	Timeout time.Duration
 }
 
+// interruptCheckFrequency is the number of comprehension iterations between
+// checks for canceled contexts.
+const interruptCheckFrequency = 100
+
 var defaultOptions = Options{
 	EnvOptions: []cel.EnvOption{
 		ext.Bindings(),
@@ -28,9 +56,35 @@ var defaultOptions = Options{
 	},
 }
 
+// DefaultOptions returns a copy of the options used by NewRunner
+func DefaultOptions() Options {
+	opts := defaultOptions
+	opts.EnvOptions = slices.Clone(defaultOptions.EnvOptions)
+	return opts
+}
+
 type Runner struct {
 	Environment *cel.Env
 	impl        Implementation
+	cache       *programCache
+	timeout     time.Duration
+
+	// libraryOptions are the options of the protobom library loaded in
+	// the environment
+	libraryOptions library.Options
+
+	// programOptions configure the evaluation limits of the programs
+	programOptions []cel.ProgramOption
+
+	// nodeEnv is the environment used to compile node queries
+	nodeEnv     *cel.Env
+	nodeEnvErr  error
+	nodeEnvOnce sync.Once
+
+	// explainEnv is the environment used to compile explained queries
+	explainEnv     *cel.Env
+	explainEnvErr  error
+	explainEnvOnce sync.Once
 }
 
 func NewRunner() (*Runner, error) {
@@ -42,25 +96,185 @@ func NewRunnerWithOptions(opts *Options) (*Runner, error) {
 	if err != nil {
 		return nil, err
 	}
+	progOpts := []cel.ProgramOption{
+		cel.InterruptCheckFrequency(interruptCheckFrequency),
+		library.InterruptibleGraphFunctions(),
+	}
+	if opts.CostLimit > 0 {
+		progOpts = append(progOpts,
+			cel.CostTracking(library.CostEstimator{}),
+			cel.CostLimit(opts.CostLimit),
+		)
+	}
+
 	runner := Runner{
 		Environment: env,
-		impl:        &defaultRunnerImplementation{},
+		impl:        &defaultRunnerImplementation{programOptions: progOpts},
+		timeout:     opts.Timeout,
+
+		libraryOptions: library.NewProtobom(opts.LibraryOptions...).Options,
+		programOptions: progOpts,
+	}
+
+	if opts.CacheSize > 0 {
+		runner.cache = newProgramCache(opts.CacheSize)
 	}
 
 	return &runner, nil
 }
 
+// LibraryOptions returns the options of the protobom library loaded in the
+// runner environment, for example the names of its variables.
+func (r *Runner) LibraryOptions() library.Options {
+	return r.libraryOptions
+}
+
+// ReadStream reads CEL code from a reader and returns it as a string
+func (r *Runner) ReadStream(reader io.Reader) (string, error) {
+	return r.impl.ReadStream(reader)
+}
+
+// PreparedQuery is a CEL expression compiled and planned once that can be
+// evaluated many times. A PreparedQuery is safe for concurrent use.
+type PreparedQuery struct {
+	// Code is the source code of the expression
+	Code string
+
+	program    cel.Program
+	outputType *cel.Type
+	impl       ProgramImplementation
+	timeout    time.Duration
+}
+
+// OutputType returns the type the expression was checked to return
+func (pq *PreparedQuery) OutputType() *cel.Type {
+	return pq.outputType
+}
+
+// Evaluate runs the prepared query with the passed variables. As with
+// Runner.Evaluate, errors returned by the CEL expression are set in the
+// returned value.
+func (pq *PreparedQuery) Evaluate(variables map[string]any) (ref.Val, error) {
+	return pq.EvaluateContext(context.Background(), variables)
+}
+
+// EvaluateContext runs the prepared query, stopping the evaluation when the
+// context is canceled or the runner timeout expires.
+func (pq *PreparedQuery) EvaluateContext(ctx context.Context, variables map[string]any) (ref.Val, error) {
+	ctx, cancel := withTimeout(ctx, pq.timeout)
+	defer cancel()
+
+	val, err := pq.impl.EvaluateProgram(ctx, pq.program, variables)
+	if err != nil {
+		return nil, fmt.Errorf("evaluation error: %w", err)
+	}
+	return val, nil
+}
+
+// withTimeout returns a context that expires after timeout. If timeout is
+// zero the context is returned as is.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Prepare compiles and plans the CEL `code` returning a query that can be
+// evaluated repeatedly without paying the compilation cost every time. If
+// the runner has a cache enabled, the prepared query is looked up and
+// stored in it.
+func (r *Runner) Prepare(code string) (*PreparedQuery, error) {
+	if r.cache != nil {
+		if pq, ok := r.cache.Get(code); ok {
+			return pq, nil
+		}
+	}
+
+	pq, err := r.prepare(r.Environment, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cache != nil {
+		r.cache.Add(code, pq)
+	}
+
+	return pq, nil
+}
+
+// prepare compiles and plans code in a CEL environment
+func (r *Runner) prepare(env *cel.Env, code string) (*PreparedQuery, error) {
+	impl, ok := r.impl.(ProgramImplementation)
+	if !ok {
+		return nil, errors.New("the runner implementation does not support prepared queries")
+	}
+
+	ast, err := r.impl.Compile(env, code)
+	if err != nil {
+		return nil, fmt.Errorf("compilation error: %w", err)
+	}
+
+	program, err := impl.Program(env, ast)
+	if err != nil {
+		return nil, fmt.Errorf("planning program: %w", err)
+	}
+
+	return &PreparedQuery{
+		Code:       code,
+		program:    program,
+		outputType: ast.OutputType(),
+		impl:       impl,
+		timeout:    r.timeout,
+	}, nil
+}
+
 // Evaluate evaluates the CEL `code“ passed as a string predefining the
 // variaables passed in `variables`. The function returns the raw ref.Val
 // meaning that any cel expression returning an error will not return err but
 // will set the err in the return value.
+//
+// If the runner has a cache, the compiled program is reused when evaluating
+// the same code again.
 func (r *Runner) Evaluate(code string, variables map[string]any) (ref.Val, error) {
+	return r.EvaluateContext(context.Background(), code, variables)
+}
+
+// EvaluateContext works as Evaluate but stops the evaluation when the
+// context is canceled or the runner timeout expires.
+func (r *Runner) EvaluateContext(ctx context.Context, code string, variables map[string]any) (ref.Val, error) {
+	if r.cache != nil {
+		pq, err := r.Prepare(code)
+		if err != nil {
+			return nil, err
+		}
+		return pq.EvaluateContext(ctx, variables)
+	}
+
 	ast, err := r.impl.Compile(r.Environment, code)
 	if err != nil {
 		return nil, fmt.Errorf("compilation error: %w", err)
 	}
 
-	val, err := r.impl.Evaluate(r.Environment, ast, variables)
+	// Implementations that cannot plan programs evaluate with no context
+	impl, ok := r.impl.(ProgramImplementation)
+	if !ok {
+		val, err := r.impl.Evaluate(r.Environment, ast, variables)
+		if err != nil {
+			return nil, fmt.Errorf("evaluation error: %w", err)
+		}
+		return val, nil
+	}
+
+	program, err := impl.Program(r.Environment, ast)
+	if err != nil {
+		return nil, fmt.Errorf("planning program: %w", err)
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	val, err := impl.EvaluateProgram(ctx, program, variables)
 	if err != nil {
 		return nil, fmt.Errorf("evaluation error: %w", err)
 	}
@@ -72,7 +286,7 @@ func (r *Runner) Evaluate(code string, variables map[string]any) (ref.Val, error
 // library loaded.
 func CreateEnvironment(opts *Options) (*cel.Env, error) {
 	envOpts := []cel.EnvOption{
-		library.NewProtobom().EnvOption(),
+		library.NewProtobom(opts.LibraryOptions...).EnvOption(),
 	}
 
 	// Add any additional environment options defined in the options
@@ -90,8 +304,9 @@ func CreateEnvironment(opts *Options) (*cel.Env, error) {
 }
 
 type varBuilderOptions struct {
-	Paths     []string
-	Documents []*sbom.Document
+	Paths          []string
+	Documents      []*sbom.Document
+	LibraryOptions []library.OptFunc
 }
 
 type VarBuilderOption func(*varBuilderOptions)
@@ -108,6 +323,14 @@ func WithDocuments(docs []*sbom.Document) VarBuilderOption {
 	}
 }
 
+// WithLibraryOptions sets the library options used to name the variables.
+// They should match the library options of the runner.
+func WithLibraryOptions(libOpts ...library.OptFunc) VarBuilderOption {
+	return func(opts *varBuilderOptions) {
+		opts.LibraryOptions = libOpts
+	}
+}
+
 // BuildVariables provides a mechanism to populate the variables
 // map that can be exposed in the CEl environment. The function
 // takes functional options to define the SBOMs that are made available
@@ -117,12 +340,26 @@ func WithDocuments(docs []*sbom.Document) VarBuilderOption {
 //	   WithPaths([]string{"sbom1.spdx.json", "sbom2.cdx.json"}),
 //	   WithDocuments(sbom.NewDocument())
 //	)
+//
+// The SBOMs are exposed as a list and as a map keyed by the file path of
+// SBOMs read from disk or the name of preloaded documents (their ID if they
+// have no name). If two SBOMs share a key, the map holds the first one.
 func BuildVariables(optsFn ...VarBuilderOption) (map[string]any, error) {
 	opts := &varBuilderOptions{}
 	for _, f := range optsFn {
 		f(opts)
 	}
+	libOpts := library.NewProtobom(opts.LibraryOptions...).Options
 	sbomList := []*elements.Document{}
+	sbomMap := map[string]*elements.Document{}
+
+	addDocument := func(key string, doc *sbom.Document) {
+		d := &elements.Document{Document: doc}
+		sbomList = append(sbomList, d)
+		if _, ok := sbomMap[key]; !ok {
+			sbomMap[key] = d
+		}
+	}
 
 	// Load the specified SBOM files
 	r := reader.New()
@@ -132,21 +369,22 @@ func BuildVariables(optsFn ...VarBuilderOption) (map[string]any, error) {
 		if err != nil {
 			return nil, fmt.Errorf("parsing %q: %w", path, err)
 		}
-		sbomList = append(sbomList, &elements.Document{
-			Document: doc,
-		})
+		addDocument(path, doc)
 	}
 
 	// Add any preloaded documents to the list:
 	for _, doc := range opts.Documents {
-		sbomList = append(sbomList, &elements.Document{
-			Document: doc,
-		})
+		key := doc.GetMetadata().GetName()
+		if key == "" {
+			key = doc.GetMetadata().GetId()
+		}
+		addDocument(key, doc)
 	}
 
 	// Add the SBOM list to the runtim environment
 	return map[string]any{
-		"protobom": elements.Protobom{},
-		"sboms":    sbomList,
+		libOpts.ProtobomVarName:   elements.Protobom{},
+		libOpts.DocsVarName:       sbomList,
+		libOpts.DocsByNameVarName: sbomMap,
 	}, nil
 }
@@ -4,6 +4,7 @@
 package runner
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -17,7 +18,22 @@ type Implementation interface {
 	Evaluate(*cel.Env, *cel.Ast, map[string]any) (ref.Val, error)
 }
 
-type defaultRunnerImplementation struct{}
+// ProgramImplementation is an optional interface for implementations that
+// plan a program once and evaluate it many times, stopping when a context is
+// canceled. The runner needs it for prepared queries, the program cache and
+// evaluations with a context or timeout.
+type ProgramImplementation interface {
+	Program(*cel.Env, *cel.Ast) (cel.Program, error)
+	EvaluateProgram(context.Context, cel.Program, map[string]any) (ref.Val, error)
+}
+
+var _ ProgramImplementation = (*defaultRunnerImplementation)(nil)
+
+type defaultRunnerImplementation struct {
+	// programOptions are applied when planning programs, they configure
+	// the evaluation limits.
+	programOptions []cel.ProgramOption
+}
 
 func (*defaultRunnerImplementation) ReadStream(reader io.Reader) (string, error) {
 	// Read all the stream into a string
@@ -36,24 +52,52 @@ func (*defaultRunnerImplementation) Compile(env *cel.Env, code string) (*cel.Ast
 	// Run the compilation step
 	ast, iss := env.Compile(code)
 	if iss.Err() != nil {
-		return nil, fmt.Errorf("compilation error: %w", iss.Err())
+		return nil, iss.Err()
 	}
 	return ast, nil
 }
 
-// EvaluateAST evaluates a CEL syntax tree on an SBOM. Returns the program
-// evaluation result or an error.
-func (*defaultRunnerImplementation) Evaluate(env *cel.Env, ast *cel.Ast, variables map[string]any) (ref.Val, error) {
-	program, err := env.Program(ast, cel.EvalOptions(cel.OptOptimize))
+// Program plans a CEL program from a checked AST. Programs are stateless and
+// safe to evaluate concurrently so they can be reused across evaluations.
+func (di *defaultRunnerImplementation) Program(env *cel.Env, ast *cel.Ast) (cel.Program, error) {
+	opts := append([]cel.ProgramOption{cel.EvalOptions(cel.OptOptimize)}, di.programOptions...)
+	program, err := env.Program(ast, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("generating program from AST: %w", err)
 	}
+	return program, nil
+}
 
-	// Run the evaluation
-	result, _, err := program.Eval(variables)
+// EvaluateAST evaluates a CEL syntax tree on an SBOM. Returns the program
+// evaluation result or an error.
+func (di *defaultRunnerImplementation) Evaluate(env *cel.Env, ast *cel.Ast, variables map[string]any) (ref.Val, error) {
+	program, err := di.Program(env, ast)
 	if err != nil {
-		return nil, fmt.Errorf("evaluation error: %w", err)
+		return nil, err
 	}
 
+	return di.EvaluateProgram(context.Background(), program, variables)
+}
+
+// EvaluateProgram runs an already planned program with the passed variables.
+// If the context can be canceled, comprehensions and the graph traversal
+// functions check it periodically and the evaluation stops with an error
+// when it is done.
+func (*defaultRunnerImplementation) EvaluateProgram(ctx context.Context, program cel.Program, variables map[string]any) (ref.Val, error) {
+	if ctx.Done() == nil {
+		result, _, err := program.Eval(variables)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	result, _, err := program.ContextEval(ctx, variables)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("evaluation interrupted: %w", context.Cause(ctx))
+		}
+		return nil, err
+	}
 	return result, nil
 }
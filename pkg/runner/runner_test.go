@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/protobom/cel/pkg/library"
+)
+
+func testDocument() *sbom.Document {
+	doc := sbom.NewDocument()
+	doc.NodeList.AddRootNode(&sbom.Node{Id: "root", Name: "root", Type: sbom.Node_PACKAGE})
+	doc.NodeList.AddNode(&sbom.Node{Id: "file", Name: "file.txt", Type: sbom.Node_FILE})
+	doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_contains, From: "root", To: []string{"file"}})
+	return doc
+}
+
+func TestPrepare(t *testing.T) {
+	r, err := NewRunner()
+	require.NoError(t, err)
+
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	pq, err := r.Prepare("size(sboms[0].get_files().get_nodes())")
+	require.NoError(t, err)
+
+	// Prepared queries can be evaluated concurrently
+	results := make([]any, 10)
+	errs := make([]error, 10)
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := pq.Evaluate(vars)
+			errs[i] = err
+			if err == nil {
+				results[i] = res.Value()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 10 {
+		require.NoError(t, errs[i])
+		require.Equal(t, int64(1), results[i])
+	}
+
+	_, err = r.Prepare("sboms[0].does_not_exist()")
+	require.Error(t, err)
+}
+
+func TestErrorMessages(t *testing.T) {
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	for _, cacheSize := range []int{0, 1} {
+		r, err := NewRunnerWithOptions(&Options{CacheSize: cacheSize})
+		require.NoError(t, err)
+
+		_, err = r.Evaluate("sboms[0].no_such_function()", vars)
+		require.Error(t, err)
+		require.Equal(t, 1, strings.Count(err.Error(), "compilation error"), err.Error())
+
+		_, err = r.Evaluate(`sboms[0].get_node_by_id(1 / 0 == 1 ? "a" : "b")`, vars)
+		require.Error(t, err)
+		require.Equal(t, 1, strings.Count(err.Error(), "evaluation error"), err.Error())
+	}
+}
+
+func TestBasicImplementation(t *testing.T) {
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	r, err := NewRunner()
+	require.NoError(t, err)
+	// Wrapping hides the optional ProgramImplementation methods
+	r.impl = &struct{ Implementation }{&defaultRunnerImplementation{}}
+
+	res, err := r.Evaluate("sboms[0].node_list.get_nodes().size()", vars)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), res.Value())
+
+	_, err = r.Prepare("sboms[0].node_list.get_nodes().size()")
+	require.Error(t, err)
+}
+
+func TestProgramCache(t *testing.T) {
+	r, err := NewRunnerWithOptions(&Options{
+		EnvOptions: defaultOptions.EnvOptions,
+		CacheSize:  2,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, r.cache)
+
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	// Evaluating the same code twice reuses the prepared query
+	_, err = r.Evaluate("sboms[0].get_node_by_id('root').name", vars)
+	require.NoError(t, err)
+	pq1, err := r.Prepare("sboms[0].get_node_by_id('root').name")
+	require.NoError(t, err)
+	pq2, err := r.Prepare("sboms[0].get_node_by_id('root').name")
+	require.NoError(t, err)
+	require.Same(t, pq1, pq2)
+	require.Equal(t, 1, r.cache.Len())
+
+	// Adding more queries than the cache size evicts the least recently used
+	_, err = r.Prepare("1 + 1")
+	require.NoError(t, err)
+	_, err = r.Prepare("sboms[0].get_node_by_id('root').name")
+	require.NoError(t, err)
+	_, err = r.Prepare("2 + 2")
+	require.NoError(t, err)
+	require.Equal(t, 2, r.cache.Len())
+
+	_, ok := r.cache.Get("1 + 1")
+	require.False(t, ok)
+	pq3, ok := r.cache.Get("sboms[0].get_node_by_id('root').name")
+	require.True(t, ok)
+	require.Same(t, pq1, pq3)
+
+	// Compilation errors are not cached
+	_, err = r.Evaluate("sboms[0].does_not_exist()", vars)
+	require.Error(t, err)
+	require.Equal(t, 2, r.cache.Len())
+}
+
+func TestBuildVariables(t *testing.T) {
+	named := testDocument()
+	named.Metadata.Name = "frontend"
+	unnamed := testDocument()
+	unnamed.Metadata.Id = "urn:uuid:backend"
+
+	vars, err := BuildVariables(
+		WithPaths([]string{"../elements/testdata/github.spdx.json"}),
+		WithDocuments([]*sbom.Document{named, unnamed}),
+	)
+	require.NoError(t, err)
+
+	r, err := NewRunner()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		code     string
+		mustErr  bool
+		expected any
+	}{
+		{"size", "size(sboms)", false, int64(3)},
+		{"all", "sboms.all(s, s.node_list.get_root_nodes().size() > 0)", false, true},
+		{"by-path", `sbom_by_name["../elements/testdata/github.spdx.json"].node_list.get_root_nodes()[0].name`, false, "com.github.kubernetes-sigs/bom"},
+		{"by-name", `sbom_by_name["frontend"].metadata.name`, false, "frontend"},
+		{"by-id", `sbom_by_name["urn:uuid:backend"].metadata.id`, false, "urn:uuid:backend"},
+		{"keys", `size(sbom_by_name)`, false, int64(3)},
+		{"missing", `sbom_by_name["missing"]`, true, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ret, err := r.Evaluate(tc.code, vars)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ret.Value())
+		})
+	}
+
+	// Variable names follow the library options
+	vars, err = BuildVariables(
+		WithDocuments([]*sbom.Document{named}),
+		WithLibraryOptions(library.WithDocsVarName("docs"), library.WithDocsByNameVarName("docs_by_name")),
+	)
+	require.NoError(t, err)
+	require.Contains(t, vars, "docs")
+	require.Contains(t, vars, "docs_by_name")
+	require.NotContains(t, vars, "sboms")
+}
+
+func TestEvaluationLimits(t *testing.T) {
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{testDocument()}))
+	require.NoError(t, err)
+
+	// A comprehension that runs for a long time
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = strconv.Itoa(i)
+	}
+	list := "[" + strings.Join(items, ",") + "]"
+	slow := fmt.Sprintf("%[1]s.map(a, %[1]s.map(b, %[1]s.map(c, %[1]s.map(d, a + b + c + d)))).size()", list)
+
+	t.Run("cost-limit", func(t *testing.T) {
+		// Each union walks the three nodes and edges of both operands
+		code := "sboms[0].union(sboms[0]).union(sboms[0]).get_nodes().size()"
+
+		r, err := NewRunnerWithOptions(&Options{CostLimit: 10})
+		require.NoError(t, err)
+		_, err = r.Evaluate(code, vars)
+		require.ErrorContains(t, err, "cost limit")
+
+		r, err = NewRunnerWithOptions(&Options{CostLimit: 100})
+		require.NoError(t, err)
+		res, err := r.Evaluate(code, vars)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), res.Value())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r, err := NewRunnerWithOptions(&Options{Timeout: 50 * time.Millisecond, CacheSize: 1})
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = r.Evaluate(slow, vars)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 5*time.Second)
+
+		// Prepared queries use the runner timeout too
+		pq, err := r.Prepare(slow)
+		require.NoError(t, err)
+		_, err = pq.Evaluate(vars)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		r, err := NewRunner()
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+		_, err = r.EvaluateContext(ctx, slow, vars)
+		require.ErrorIs(t, err, context.Canceled)
+
+		res, err := r.EvaluateContext(context.Background(), "sboms[0].node_list.get_nodes().size()", vars)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), res.Value())
+	})
+
+	t.Run("graph-traversal", func(t *testing.T) {
+		// A ladder of 60 steps has 2^60 paths to its top that never reach
+		// the unconnected sink node.
+		doc := sbom.NewDocument()
+		doc.NodeList.AddRootNode(&sbom.Node{Id: "a0"})
+		doc.NodeList.AddNode(&sbom.Node{Id: "b0"})
+		doc.NodeList.AddNode(&sbom.Node{Id: "sink"})
+		for i := 1; i <= 60; i++ {
+			a, b := fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+			doc.NodeList.AddNode(&sbom.Node{Id: a})
+			doc.NodeList.AddNode(&sbom.Node{Id: b})
+			for _, from := range []string{fmt.Sprintf("a%d", i-1), fmt.Sprintf("b%d", i-1)} {
+				doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: from, To: []string{a, b}})
+			}
+		}
+		vars, err := BuildVariables(WithDocuments([]*sbom.Document{doc}))
+		require.NoError(t, err)
+
+		r, err := NewRunnerWithOptions(&Options{Timeout: 50 * time.Millisecond})
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = r.Evaluate(`sboms[0].node_list.get_paths("a0", "sink", 100).size()`, vars)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
+func TestRepeatedEvaluations(t *testing.T) {
+	r, err := NewRunner()
+	require.NoError(t, err)
+
+	doc := testDocument()
+	doc.NodeList.AddNode(&sbom.Node{Id: "lib", Name: "lib", Type: sbom.Node_PACKAGE})
+	doc.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "root", To: []string{"lib"}})
+	original := proto.Clone(doc)
+
+	vars, err := BuildVariables(WithDocuments([]*sbom.Document{doc}))
+	require.NoError(t, err)
+
+	// Each expression is evaluated twice: functions must not modify the
+	// documents, so the results and the variables have to be stable.
+	for _, code := range []string{
+		"sboms[0].get_packages().get_edges().size()",
+		"sboms[0].get_files().to_document().node_list.root_elements.size()",
+		`sboms[0].relate_node_list_at_id(sboms[0].get_node_by_id("file").to_node_list(), "lib", "CONTAINS").node_list.get_edges().size()`,
+		`sboms[0].replace_node(sboms[0].get_node_by_id("lib").with_version("2.0")).get_node_by_id("lib").version`,
+		"sboms[0].node_list.get_edges().map(e, e.to.size())",
+	} {
+		t.Run(code, func(t *testing.T) {
+			first, err := r.Evaluate(code, vars)
+			require.NoError(t, err)
+			second, err := r.Evaluate(code, vars)
+			require.NoError(t, err)
+			require.Equal(t, first.Value(), second.Value())
+			require.True(t, proto.Equal(original, doc), "document was modified")
+		})
+	}
+}
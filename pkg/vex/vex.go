@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+// Package vex reads OpenVEX documents and resolves the exploitability status
+// of vulnerabilities in software products.
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/package-url/packageurl-go"
+)
+
+// Status labels defined by the OpenVEX specification
+const (
+	StatusNotAffected        = "not_affected"
+	StatusAffected           = "affected"
+	StatusFixed              = "fixed"
+	StatusUnderInvestigation = "under_investigation"
+)
+
+// Document is an OpenVEX document. The version is a number, written as a
+// string in the early versions of the spec.
+type Document struct {
+	Context    string      `json:"@context"`
+	ID         string      `json:"@id"`
+	Author     string      `json:"author"`
+	Timestamp  *time.Time  `json:"timestamp"`
+	Version    json.Number `json:"version"`
+	Statements []Statement `json:"statements"`
+}
+
+// Statement asserts the status of a vulnerability in a list of products
+type Statement struct {
+	Vulnerability   Vulnerability `json:"vulnerability"`
+	Products        []Product     `json:"products"`
+	Status          string        `json:"status"`
+	Justification   string        `json:"justification"`
+	ImpactStatement string        `json:"impact_statement"`
+	ActionStatement string        `json:"action_statement"`
+	Timestamp       *time.Time    `json:"timestamp"`
+}
+
+// Vulnerability identifies the vulnerability of a statement
+type Vulnerability struct {
+	ID      string   `json:"@id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// UnmarshalJSON reads a vulnerability from an object or, as in the early
+// versions of the spec, from a plain string with its name.
+func (v *Vulnerability) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*v = Vulnerability{Name: name}
+		return nil
+	}
+	type vulnerability Vulnerability
+	return json.Unmarshal(data, (*vulnerability)(v))
+}
+
+// Matches checks if the vulnerability is known by an ID, either its name,
+// @id or one of its aliases.
+func (v *Vulnerability) Matches(id string) bool {
+	if id == "" {
+		return false
+	}
+	return v.Name == id || v.ID == id || slices.Contains(v.Aliases, id)
+}
+
+// Component is a piece of software referenced in a statement
+type Component struct {
+	ID          string            `json:"@id"`
+	Identifiers map[string]string `json:"identifiers"`
+	Hashes      map[string]string `json:"hashes"`
+}
+
+// UnmarshalJSON reads a component from an object or, as in the early
+// versions of the spec, from a plain string with its ID.
+func (c *Component) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*c = Component{ID: id}
+		return nil
+	}
+	type component Component
+	return json.Unmarshal(data, (*component)(c))
+}
+
+// Product is a component a statement applies to, along with the
+// subcomponents where the vulnerability may reside.
+type Product struct {
+	Component
+	Subcomponents []Component `json:"subcomponents"`
+}
+
+// UnmarshalJSON reads the product component and its subcomponents. It is
+// needed as the embedded component unmarshaller would hide the
+// subcomponents.
+func (p *Product) UnmarshalJSON(data []byte) error {
+	if err := p.Component.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	p.Subcomponents = nil
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil
+	}
+	var subs struct {
+		Subcomponents []Component `json:"subcomponents"`
+	}
+	if err := json.Unmarshal(data, &subs); err != nil {
+		return err
+	}
+	p.Subcomponents = subs.Subcomponents
+	return nil
+}
+
+// Matches checks if the component refers to a piece of software known by
+// a list of identifiers, eg its package URL or CPE. Identifiers are matched
+// against the component @id and its identifiers. Package URLs are compared
+// in their canonical form ignoring qualifiers and subpath, and a purl
+// without version matches all the versions of the package.
+func (c *Component) Matches(identifiers ...string) bool {
+	ids := []string{c.ID}
+	for _, v := range c.Identifiers {
+		ids = append(ids, v)
+	}
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		for _, other := range identifiers {
+			if other != "" && (id == other || purlMatches(id, other)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Matches checks if the product or any of its subcomponents are known by one
+// of the identifiers.
+func (p *Product) Matches(identifiers ...string) bool {
+	if p.Component.Matches(identifiers...) {
+		return true
+	}
+	for i := range p.Subcomponents {
+		if p.Subcomponents[i].Matches(identifiers...) {
+			return true
+		}
+	}
+	return false
+}
+
+// purlMatches compares a package URL in a VEX document against the purl of
+// a piece of software. Returns false if any of them is not a purl.
+func purlMatches(vexPurl, purl string) bool {
+	if !strings.HasPrefix(vexPurl, "pkg:") || !strings.HasPrefix(purl, "pkg:") {
+		return false
+	}
+	vp, err := packageurl.FromString(vexPurl)
+	if err != nil {
+		return false
+	}
+	p, err := packageurl.FromString(purl)
+	if err != nil {
+		return false
+	}
+	if vp.Type != p.Type || vp.Namespace != p.Namespace || vp.Name != p.Name {
+		return false
+	}
+	return vp.Version == "" || vp.Version == p.Version
+}
+
+// StatementFor returns the statement that determines the status of a
+// vulnerability in a piece of software known by a list of identifiers.
+// When more than one statement applies, the most recent one wins. Returns
+// nil if no statement applies.
+func (d *Document) StatementFor(vulnID string, identifiers ...string) *Statement {
+	var ret *Statement
+	var retTime time.Time
+	for i := range d.Statements {
+		s := &d.Statements[i]
+		if !s.Vulnerability.Matches(vulnID) {
+			continue
+		}
+		if !slices.ContainsFunc(s.Products, func(p Product) bool {
+			return p.Matches(identifiers...)
+		}) {
+			continue
+		}
+
+		// Statements without a timestamp inherit the one of the document.
+		// Later statements win ties as in the document order.
+		t := d.statementTime(s)
+		if ret == nil || !t.Before(retTime) {
+			ret, retTime = s, t
+		}
+	}
+	return ret
+}
+
+// StatusFor returns the status of a vulnerability in a piece of software
+// known by a list of identifiers or an empty string if no statement
+// applies to it.
+func (d *Document) StatusFor(vulnID string, identifiers ...string) string {
+	if s := d.StatementFor(vulnID, identifiers...); s != nil {
+		return s.Status
+	}
+	return ""
+}
+
+// statementTime returns the time of a statement
+func (d *Document) statementTime(s *Statement) time.Time {
+	switch {
+	case s.Timestamp != nil:
+		return *s.Timestamp
+	case d.Timestamp != nil:
+		return *d.Timestamp
+	default:
+		return time.Time{}
+	}
+}
+
+// Parse reads an OpenVEX document
+func Parse(data []byte) (*Document, error) {
+	doc := &Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenVEX document: %w", err)
+	}
+	if !strings.HasPrefix(doc.Context, "https://openvex.dev/ns") {
+		return nil, errors.New("parsing OpenVEX document: not an OpenVEX document")
+	}
+	for i := range doc.Statements {
+		if doc.Statements[i].Status == "" {
+			return nil, fmt.Errorf("parsing OpenVEX document: statement #%d has no status", i)
+		}
+	}
+	return doc, nil
+}
+
+// Load reads an OpenVEX document from a file
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading OpenVEX document: %w", err)
+	}
+	return Parse(data)
+}
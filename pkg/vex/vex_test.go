@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: Copyright 2025 The Protobom Authors
+
+package vex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		path       string
+		statements int
+		mustErr    bool
+	}{
+		{"v0.2.0", "testdata/bom.openvex.json", 4, false},
+		{"legacy", "testdata/legacy.openvex.json", 1, false},
+		{"missing", "testdata/nope.json", 0, true},
+		{"not-json", "vex.go", 0, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := Load(tc.path)
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, doc.Statements, tc.statements)
+		})
+	}
+
+	legacy, err := Load("testdata/legacy.openvex.json")
+	require.NoError(t, err)
+	require.Equal(t, "CVE-2023-12345", legacy.Statements[0].Vulnerability.Name)
+	require.Equal(t, "pkg:apk/wolfi/git@2.39.0-r1?arch=x86_64", legacy.Statements[0].Products[0].ID)
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		data    string
+		mustErr bool
+	}{
+		{"valid", `{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [{"vulnerability": {"name": "CVE-1"}, "products": [{"@id": "pkg:npm/a"}], "status": "fixed"}]}`, false},
+		{"no-context", `{"statements": []}`, true},
+		{"no-status", `{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [{"vulnerability": {"name": "CVE-1"}}]}`, true},
+		{"bad-subcomponents", `{"@context": "https://openvex.dev/ns/v0.2.0", "statements": [{"vulnerability": "CVE-1", "status": "fixed", "products": [{"@id": "a", "subcomponents": "b"}]}]}`, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			if tc.mustErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	doc, err := Load("testdata/bom.openvex.json")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		vulnID      string
+		identifiers []string
+		expected    string
+	}{
+		{"purl", "CVE-2024-4068", []string{"pkg:npm/braces@3.0.2"}, StatusNotAffected},
+		{"alias", "GHSA-grv7-fg5c-xmjg", []string{"pkg:npm/braces@3.0.2"}, StatusNotAffected},
+		{"qualifiers-ignored", "CVE-2024-4068", []string{"pkg:npm/braces@3.0.2?foo=bar"}, StatusNotAffected},
+		{"other-version", "CVE-2024-4068", []string{"pkg:npm/braces@3.0.3"}, ""},
+		{"other-vulnerability", "CVE-2024-0001", []string{"pkg:npm/braces@3.0.2"}, ""},
+		{"subcomponent-versionless", "GHSA-7fh5-64p2-3v2j", []string{"pkg:npm/postcss@7.0.39"}, StatusAffected},
+		{"product", "GHSA-7fh5-64p2-3v2j", []string{"pkg:github/kubernetes-sigs/bom@v0.6.0"}, StatusAffected},
+		{"identifiers", "CVE-2023-48795", []string{"pkg:golang/golang.org/x/crypto@0.11.0"}, StatusNotAffected},
+		{"vulnerability-id", "https://pkg.go.dev/vuln/GO-2023-2402", []string{"pkg:golang/golang.org/x/crypto@0.11.0"}, StatusNotAffected},
+		{"product-id", "GO-2023-2402", []string{"https://example.com/artifacts/x-crypto"}, StatusNotAffected},
+		{"no-identifiers", "GO-2023-2402", []string{}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, doc.StatusFor(tc.vulnID, tc.identifiers...))
+		})
+	}
+
+	// The latest statement wins
+	s := doc.StatementFor("GHSA-7fh5-64p2-3v2j", "pkg:npm/postcss@7.0.39")
+	require.NotNil(t, s)
+	require.Equal(t, "Update postcss to 8.4.31", s.ActionStatement)
+}